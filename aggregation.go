@@ -0,0 +1,461 @@
+package weaklinq
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+//----------------------------------------------------------------------------//
+// Aggregation                                                                //
+//----------------------------------------------------------------------------//
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Reducer is a named aggregate computed over the items grouped under each key
+// of a MapIterable, such as a sum or a count. Reducers are applied one item at
+// a time as the MapIterable is iterated, so the grouped items themselves are
+// never held in memory. Reducers read from the result of the item selector,
+// so after Group or GroupListsOf they read the selected values, and after
+// GroupBy or GroupListsBy they read the items themselves.
+type Reducer struct {
+	name     string
+	selector func(any) any
+	reduce   func(accumulator any, value any) any
+	result   func(accumulator any, count int) any
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// As returns a copy of the Reducer with the given name. When aggregating into
+// a struct, the name is the field that the aggregate is assigned to.
+func (reducer Reducer) As(name string) Reducer {
+
+	reducer.name = name
+	return reducer
+
+	/*
+		linq.Sum("Revenue").As("TotalRevenue")
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Count returns a Reducer that counts the items under each key, as an int.
+// Its name is "Count".
+func Count() Reducer {
+
+	return Reducer{
+		name:     "Count",
+		selector: func(any) any { return nil },
+		reduce:   func(any, any) any { return nil },
+		result:   func(_ any, count int) any { return count },
+	}
+
+	/*
+		linq.Count()
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// sumReducer returns a Reducer that adds up the selected values, keeping the
+// type of the values.
+func sumReducer(name string, selector func(any) any) Reducer {
+
+	return Reducer{
+		name:     name,
+		selector: selector,
+		reduce: func(accumulator any, value any) any {
+			if accumulator == nil {
+				return value
+			}
+			return addValues(accumulator, value)
+		},
+		result: func(accumulator any, _ int) any { return accumulator },
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// addValues returns the sum of a and b, in the type of a. The value b must be
+// convertible to the type of a. If the values are not numbers, or an integer
+// or unsigned sum does not fit in the type of a, this function will panic.
+func addValues(a any, b any) any {
+
+	aValue := reflect.ValueOf(a)
+	bValue := reflect.ValueOf(b)
+	if !aValue.IsValid() || !bValue.IsValid() || !bValue.CanConvert(aValue.Type()) {
+		panic(fmt.Sprintf("cannot add %T to %T", b, a))
+	}
+
+	bValue = bValue.Convert(aValue.Type())
+	result := reflect.New(aValue.Type()).Elem()
+
+	switch aValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		sum := aValue.Int() + bValue.Int()
+		if (bValue.Int() > 0 && sum < aValue.Int()) || (bValue.Int() < 0 && sum > aValue.Int()) || result.OverflowInt(sum) {
+			panic(fmt.Sprintf("sum of %v and %v overflows %T", a, b, a))
+		}
+		result.SetInt(sum)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		sum := aValue.Uint() + bValue.Uint()
+		if sum < aValue.Uint() || result.OverflowUint(sum) {
+			panic(fmt.Sprintf("sum of %v and %v overflows %T", a, b, a))
+		}
+		result.SetUint(sum)
+	case reflect.Float32, reflect.Float64:
+		result.SetFloat(aValue.Float() + bValue.Float())
+	default:
+		panic(fmt.Sprintf("values of type %T are not numeric", a))
+	}
+
+	return result.Interface()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// SumThis returns a Reducer that adds up the values returned by the given
+// selector for the items under each key. The values must be numbers, and the
+// sum keeps their type. Its name is "Sum". If an integer sum overflows its
+// type, iterating will panic.
+func SumThis[T any](selector func(T) any) Reducer {
+
+	return sumReducer("Sum", func(item any) any { return selector(item.(T)) })
+
+	/*
+		linq.SumThis(
+			func(item T) any {
+				return item.ValueField
+			},
+		)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Sum returns a Reducer that adds up the given field of the items under each
+// key. The field must be a number, and the sum keeps its type. Its name is
+// "Sum" followed by the field name. If an item is not a struct, fieldName is
+// not found, or an integer sum overflows its type, iterating will panic.
+func Sum(fieldName string) Reducer {
+
+	return sumReducer("Sum"+fieldName, getFieldNameFunc[any](fieldName))
+
+	/*
+		linq.Sum("ValueField")
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// extremeReducer returns a Reducer that keeps the selected value that compares
+// first in the given direction: -1 for the minimum and 1 for the maximum.
+func extremeReducer(name string, selector func(any) any, direction int) Reducer {
+
+	return Reducer{
+		name:     name,
+		selector: selector,
+		reduce: func(accumulator any, value any) any {
+			if accumulator == nil || compareValues(value, accumulator) == direction {
+				return value
+			}
+			return accumulator
+		},
+		result: func(accumulator any, _ int) any { return accumulator },
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// MinThis returns a Reducer that keeps the smallest of the values returned by
+// the given selector for the items under each key. The values must be
+// numbers, strings or time.Time values of the same type. Its name is "Min".
+func MinThis[T any](selector func(T) any) Reducer {
+
+	return extremeReducer("Min", func(item any) any { return selector(item.(T)) }, -1)
+
+	/*
+		linq.MinThis(
+			func(item T) any {
+				return item.ValueField
+			},
+		)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Min returns a Reducer that keeps the smallest value of the given field of
+// the items under each key. The field must be a number, string or time.Time.
+// Its name is "Min" followed by the field name. If an item is not a struct,
+// or fieldName is not found, iterating will panic.
+func Min(fieldName string) Reducer {
+
+	return extremeReducer("Min"+fieldName, getFieldNameFunc[any](fieldName), -1)
+
+	/*
+		linq.Min("ValueField")
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// MaxThis returns a Reducer that keeps the largest of the values returned by
+// the given selector for the items under each key. The values must be
+// numbers, strings or time.Time values of the same type. Its name is "Max".
+func MaxThis[T any](selector func(T) any) Reducer {
+
+	return extremeReducer("Max", func(item any) any { return selector(item.(T)) }, 1)
+
+	/*
+		linq.MaxThis(
+			func(item T) any {
+				return item.ValueField
+			},
+		)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Max returns a Reducer that keeps the largest value of the given field of
+// the items under each key. The field must be a number, string or time.Time.
+// Its name is "Max" followed by the field name. If an item is not a struct,
+// or fieldName is not found, iterating will panic.
+func Max(fieldName string) Reducer {
+
+	return extremeReducer("Max"+fieldName, getFieldNameFunc[any](fieldName), 1)
+
+	/*
+		linq.Max("ValueField")
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// avgReducer returns a Reducer that averages the selected values as a float64.
+func avgReducer(name string, selector func(any) any) Reducer {
+
+	return Reducer{
+		name:     name,
+		selector: selector,
+		reduce: func(accumulator any, value any) any {
+			if accumulator == nil {
+				return floatValue(value)
+			}
+			return accumulator.(float64) + floatValue(value)
+		},
+		result: func(accumulator any, count int) any { return accumulator.(float64) / float64(count) },
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AvgThis returns a Reducer that averages the values returned by the given
+// selector for the items under each key, as a float64. The values must be
+// numbers. Its name is "Avg".
+func AvgThis[T any](selector func(T) any) Reducer {
+
+	return avgReducer("Avg", func(item any) any { return selector(item.(T)) })
+
+	/*
+		linq.AvgThis(
+			func(item T) any {
+				return item.ValueField
+			},
+		)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Avg returns a Reducer that averages the given field of the items under each
+// key, as a float64. The field must be a number. Its name is "Avg" followed
+// by the field name. If an item is not a struct, or fieldName is not found,
+// iterating will panic.
+func Avg(fieldName string) Reducer {
+
+	return avgReducer("Avg"+fieldName, getFieldNameFunc[any](fieldName))
+
+	/*
+		linq.Avg("ValueField")
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Aggregate returns a new MapIterable where the value for each key is computed
+// by the given reducers over the items under that key, in a single pass. When
+// assigned to a map, the map value may be a struct with a field named after
+// each reducer, the result type of a single reducer, or a []any holding the
+// results in reducer order. If no reducers are given, this function will
+// panic.
+func (iterable MapIterable[T]) Aggregate(reducers ...Reducer) MapIterable[T] {
+
+	if len(reducers) == 0 {
+		panic("at least one reducer must be given")
+	}
+
+	iterable.reducers = reducers
+	return iterable
+
+	/*
+		result := make(map[K]struct{ SumValueField int; Count int })
+		linq.From([]T{...}).
+			GroupBy("KeyField").
+			Aggregate(linq.Sum("ValueField"), linq.Count()).
+			AndAssignToMap(&result)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Aggregate returns a new DeferredKeyMapIterable where the value for each key
+// will be computed by the given reducers over the selected values, as with
+// MapIterable.Aggregate. Should be used in tandem with the By functions. If no
+// reducers are given, this function will panic.
+func (iterable DeferredKeyMapIterable[T]) Aggregate(reducers ...Reducer) DeferredKeyMapIterable[T] {
+
+	return DeferredKeyMapIterable[T](MapIterable[T](iterable).Aggregate(reducers...))
+
+	/*
+		linq.From([]T{...}).
+			GroupListsOf("ValueField").
+			Aggregate(linq.MaxThis(func(value V) any { return value })).
+			By("KeyField")
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// aggregates iterates over the MapIterable, applying its reducers to the items
+// under each key, and returns the keys in the order they were first seen along
+// with the results of the reducers for each key.
+func (iterable MapIterable[T]) aggregates() ([]any, map[any][]any) {
+
+	type aggregate struct {
+		accumulators []any
+		count        int
+	}
+
+	keys := make([]any, 0)
+	aggregates := make(map[any]*aggregate)
+	for item := range iterable.itemIterable.Seq {
+
+		key := iterable.groupKey(item)
+		current, exists := aggregates[key]
+		if !exists {
+			current = &aggregate{accumulators: make([]any, len(iterable.reducers))}
+			aggregates[key] = current
+			keys = append(keys, key)
+		}
+
+		current.count++
+		value := iterable.itemSelector(item)
+		for i, reducer := range iterable.reducers {
+			current.accumulators[i] = reducer.reduce(current.accumulators[i], reducer.selector(value))
+		}
+	}
+
+	results := make(map[any][]any, len(keys))
+	for _, key := range keys {
+		current := aggregates[key]
+		results[key] = make([]any, len(iterable.reducers))
+		for i, reducer := range iterable.reducers {
+			results[key][i] = reducer.result(current.accumulators[i], current.count)
+		}
+	}
+
+	return keys, results
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// aggregateValue returns the results of the given reducers as a value of the
+// given type: a struct with a field named after each reducer, the result of a
+// single reducer, or a []any of the results. If the results cannot be
+// assigned to the type, this function will panic.
+func aggregateValue(valueType reflect.Type, reducers []Reducer, results []any) reflect.Value {
+
+	if valueType.Kind() == reflect.Struct {
+		value := reflect.New(valueType).Elem()
+		for i, reducer := range reducers {
+			field := value.FieldByName(reducer.name)
+			if !field.IsValid() {
+				panic(fmt.Sprintf("field name '%s' not found in struct %v", reducer.name, valueType))
+			}
+			field.Set(convertedValue(reducer.name, results[i], field.Type()))
+		}
+		return value
+	}
+
+	if len(reducers) == 1 {
+		return convertedValue(reducers[0].name, results[0], valueType)
+	}
+
+	return convertedValue("aggregate", results, valueType)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// convertedValue returns the given result as a value of the given type.
+// Numbers may be converted to other numeric types if they fit in the type, and
+// floats may only be converted to integer types if they are whole. Otherwise
+// the result must be assignable to the type. If it is not, this function will
+// panic.
+func convertedValue(name string, result any, valueType reflect.Type) reflect.Value {
+
+	value := reflect.ValueOf(result)
+	if value.IsValid() && value.Type().AssignableTo(valueType) {
+		return value
+	}
+
+	if !value.IsValid() || !isNumericKind(value.Kind()) || !isNumericKind(valueType.Kind()) {
+		panic(fmt.Sprintf("'%s' is %T, not %v", name, result, valueType))
+	}
+
+	converted := reflect.New(valueType).Elem()
+	var fits bool
+	switch {
+	case value.CanInt() && converted.CanInt():
+		fits = !converted.OverflowInt(value.Int())
+	case value.CanInt() && converted.CanUint():
+		fits = value.Int() >= 0 && !converted.OverflowUint(uint64(value.Int()))
+	case value.CanUint() && converted.CanInt():
+		fits = value.Uint() <= math.MaxInt64 && !converted.OverflowInt(int64(value.Uint()))
+	case value.CanUint() && converted.CanUint():
+		fits = !converted.OverflowUint(value.Uint())
+	case value.CanFloat() && converted.CanFloat():
+		fits = !converted.OverflowFloat(value.Float())
+	case value.CanFloat():
+		if value.Float() != math.Trunc(value.Float()) {
+			panic(fmt.Sprintf("'%s' is %v, which is not a whole %v", name, result, valueType))
+		}
+		if converted.CanInt() {
+			fits = value.Float() >= math.MinInt64 && value.Float() < math.MaxInt64 && !converted.OverflowInt(int64(value.Float()))
+		} else {
+			fits = value.Float() >= 0 && value.Float() < math.MaxUint64 && !converted.OverflowUint(uint64(value.Float()))
+		}
+	default:
+		fits = true
+	}
+
+	if !fits {
+		panic(fmt.Sprintf("'%s' is %v, which does not fit in %v", name, result, valueType))
+	}
+
+	return value.Convert(valueType)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// isNumericKind returns whether the given kind is an integer, unsigned integer
+// or float kind.
+func isNumericKind(kind reflect.Kind) bool {
+
+	return (kind >= reflect.Int && kind <= reflect.Uintptr) ||
+		kind == reflect.Float32 ||
+		kind == reflect.Float64
+}
@@ -0,0 +1,314 @@
+package weaklinq
+
+import (
+	"testing"
+	"time"
+)
+
+//----------------------------------------------------------------------------//
+// Aggregation                                                                //
+//----------------------------------------------------------------------------//
+
+////////////////////////////////////////////////////////////////////////////////
+
+type testSale struct {
+	Region  string
+	Revenue int
+	Date    time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func testSales() []testSale {
+
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []testSale{
+		{Region: "East", Revenue: 10, Date: day},
+		{Region: "West", Revenue: 5, Date: day.AddDate(0, 0, 1)},
+		{Region: "East", Revenue: 30, Date: day.AddDate(0, 0, 2)},
+		{Region: "East", Revenue: 20, Date: day.AddDate(0, 0, 1)},
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestAggregate(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("struct", func(t *testing.T) {
+
+		type summary struct {
+			SumRevenue int
+			Count      int
+			MaxDate    time.Time
+			MinRevenue int
+			AvgRevenue float64
+		}
+
+		result := make(map[string]summary)
+		From(testSales()).
+			GroupBy("Region").
+			Aggregate(Sum("Revenue"), Count(), Max("Date"), Min("Revenue"), Avg("Revenue")).
+			AndAssignToMap(&result)
+
+		expected := summary{
+			SumRevenue: 60,
+			Count:      3,
+			MaxDate:    testSales()[2].Date,
+			MinRevenue: 10,
+			AvgRevenue: 20,
+		}
+		if result["East"] != expected {
+			t.Errorf("Expected %v but got %v", expected, result["East"])
+		}
+
+		if result["West"].Count != 1 || result["West"].SumRevenue != 5 {
+			t.Errorf("Expected one sale of 5 but got %v", result["West"])
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("renamed", func(t *testing.T) {
+
+		type summary struct {
+			Total int
+			Sales int
+		}
+
+		result := make(map[string]summary)
+		From(testSales()).
+			GroupBy("Region").
+			Aggregate(Sum("Revenue").As("Total"), Count().As("Sales")).
+			AndAssignToMap(&result)
+
+		if result["East"] != (summary{Total: 60, Sales: 3}) {
+			t.Errorf("Expected {60 3} but got %v", result["East"])
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("single value", func(t *testing.T) {
+
+		result := make(map[string]int)
+		From(testSales()).
+			GroupBy("Region").
+			Aggregate(Sum("Revenue")).
+			AndAssignToMap(&result)
+
+		if result["East"] != 60 || result["West"] != 5 {
+			t.Errorf("Expected East 60 and West 5 but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("converted value", func(t *testing.T) {
+
+		result := make(map[string]float64)
+		From(testSales()).
+			GroupBy("Region").
+			Aggregate(Sum("Revenue")).
+			AndAssignToMap(&result)
+
+		if result["East"] != 60 {
+			t.Errorf("Expected 60 but got %v", result["East"])
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("list of values", func(t *testing.T) {
+
+		result := make(map[string][]any)
+		From(testSales()).
+			GroupBy("Region").
+			Aggregate(Count(), Max("Revenue")).
+			AndAssignToMap(&result)
+
+		if len(result["East"]) != 2 || result["East"][0] != 3 || result["East"][1] != 30 {
+			t.Errorf("Expected [3 30] but got %v", result["East"])
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("selectors", func(t *testing.T) {
+
+		type summary struct {
+			Sum float64
+			Min string
+			Max string
+			Avg float64
+		}
+
+		result := make(map[bool]summary)
+		From(testSales()).
+			GroupByThis(func(sale testSale) any { return sale.Revenue >= 10 }).
+			Aggregate(
+				SumThis(func(sale testSale) any { return float64(sale.Revenue) / 2 }),
+				MinThis(func(sale testSale) any { return sale.Region }),
+				MaxThis(func(sale testSale) any { return sale.Region }),
+				AvgThis(func(sale testSale) any { return sale.Revenue }),
+			).
+			AndAssignToMap(&result)
+
+		expected := summary{Sum: 30, Min: "East", Max: "East", Avg: 20}
+		if result[true] != expected {
+			t.Errorf("Expected %v but got %v", expected, result[true])
+		}
+
+		expected = summary{Sum: 2.5, Min: "West", Max: "West", Avg: 5}
+		if result[false] != expected {
+			t.Errorf("Expected %v but got %v", expected, result[false])
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("deferred key", func(t *testing.T) {
+
+		result := make(map[string]int)
+		From(testSales()).
+			GroupListsOf("Revenue").
+			Aggregate(MaxThis(func(revenue int) any { return revenue })).
+			By("Region").
+			AndAssignToMap(&result)
+
+		if result["East"] != 30 || result["West"] != 5 {
+			t.Errorf("Expected East 30 and West 5 but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("no reducers", func(t *testing.T) {
+
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("Expected panic but got none")
+			}
+		}()
+
+		From(testSales()).GroupBy("Region").Aggregate()
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("missing field", func(t *testing.T) {
+
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("Expected panic but got none")
+			}
+		}()
+
+		result := make(map[string]struct{ Total int })
+		From(testSales()).
+			GroupBy("Region").
+			Aggregate(Sum("Revenue")).
+			AndAssignToMap(&result)
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("sum overflow", func(t *testing.T) {
+
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("Expected panic but got none")
+			}
+		}()
+
+		type reading struct {
+			Key   string
+			Value uint8
+		}
+
+		result := make(map[string]int)
+		From([]reading{{"a", 200}, {"a", 100}}).
+			GroupBy("Key").
+			Aggregate(Sum("Value")).
+			AndAssignToMap(&result)
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("converted overflow", func(t *testing.T) {
+
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("Expected panic but got none")
+			}
+		}()
+
+		type order struct {
+			Region string
+			Qty    int
+		}
+
+		result := make(map[string]struct{ SumQty int8 })
+		From([]order{{"East", 100}, {"East", 100}}).
+			GroupBy("Region").
+			Aggregate(Sum("Qty")).
+			AndAssignToMap(&result)
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("converted fraction", func(t *testing.T) {
+
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("Expected panic but got none")
+			}
+		}()
+
+		type order struct {
+			Region string
+			Qty    int
+		}
+
+		result := make(map[string]int)
+		From([]order{{"East", 1}, {"East", 2}}).
+			GroupBy("Region").
+			Aggregate(Avg("Qty")).
+			AndAssignToMap(&result)
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("converted whole float", func(t *testing.T) {
+
+		result := make(map[string]int)
+		From(testSales()).
+			GroupBy("Region").
+			Aggregate(Avg("Revenue")).
+			AndAssignToMap(&result)
+
+		if result["East"] != 20 || result["West"] != 5 {
+			t.Errorf("Expected East 20 and West 5 but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("type mismatch", func(t *testing.T) {
+
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("Expected panic but got none")
+			}
+		}()
+
+		result := make(map[string]string)
+		From(testSales()).
+			GroupBy("Region").
+			Aggregate(Count()).
+			AndAssignToMap(&result)
+	})
+
+	//----------------------------------------------------------------------------//
+}
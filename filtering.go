@@ -1,5 +1,7 @@
 package weaklinq
 
+import "fmt"
+
 //----------------------------------------------------------------------------//
 // Filtering                                                                  //
 //----------------------------------------------------------------------------//
@@ -54,13 +56,14 @@ func (iterable Iterable[T]) FilterOn(fieldName string) Iterable[T] {
 
 ////////////////////////////////////////////////////////////////////////////////
 
-// Distinct returns a new Iterable where the items are distinct.
+// Distinct returns a new Iterable where the items are distinct. The items
+// already seen are tracked per iteration, so the Iterable can be iterated
+// again, or by several goroutines at once.
 func (iterable Iterable[T]) Distinct() Iterable[T] {
 
-	seen := make(map[any]bool)
-
 	return Iterable[T]{
 		Seq: func(yield func(T) bool) {
+			seen := make(map[any]bool)
 			iterable.Seq(func(item T) bool {
 				if _, ok := seen[item]; !ok {
 					seen[item] = true
@@ -76,3 +79,117 @@ func (iterable Iterable[T]) Distinct() Iterable[T] {
 			Distinct()
 	*/
 }
+
+////////////////////////////////////////////////////////////////////////////////
+
+// PartitionOnThis iterates over the Iterable once and returns the items for
+// which the given predicate returns true, followed by the items for which it
+// returns false, each in their original order. The predicate is called once
+// per item.
+func (iterable Iterable[T]) PartitionOnThis(predicate func(T) bool) ([]T, []T) {
+
+	matched := make([]T, 0)
+	unmatched := make([]T, 0)
+	for item := range iterable.Seq {
+		if predicate(item) {
+			matched = append(matched, item)
+		} else {
+			unmatched = append(unmatched, item)
+		}
+	}
+
+	return matched, unmatched
+
+	/*
+		matched, unmatched := linq.From([]T{...}).
+			PartitionOnThis(
+				func(item T) bool {
+					return boolExpression
+				},
+			)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// PartitionOn iterates over the Iterable once and returns the items where the
+// given bool field is true, followed by the items where it is false. If T is
+// not a struct, or fieldName is not found, this function will panic.
+func (iterable Iterable[T]) PartitionOn(fieldName string) ([]T, []T) {
+
+	nameFunc := getFieldNameFunc[T](fieldName)
+
+	return iterable.PartitionOnThis(
+		func(item T) bool {
+			return nameFunc(item).(bool)
+		},
+	)
+
+	/*
+		active, inactive := linq.From([]T{...}).
+			PartitionOn("BoolFieldName")
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// RouteThis iterates over the Iterable once and appends each item to the sink
+// for its key, as returned by the given key selector. Items whose key has no
+// sink are returned, in their original order. The key selector is called once
+// per item. If any sink is nil, this function will panic.
+func (iterable Iterable[T]) RouteThis(keySelector func(T) any, sinks map[any]*[]T) []T {
+
+	for key, sink := range sinks {
+		if sink == nil {
+			panic(fmt.Sprintf("sink for key %v is nil", key))
+		}
+	}
+
+	unrouted := make([]T, 0)
+	for item := range iterable.Seq {
+		if sink, ok := sinks[keySelector(item)]; ok {
+			*sink = append(*sink, item)
+		} else {
+			unrouted = append(unrouted, item)
+		}
+	}
+
+	return unrouted
+
+	/*
+		var pending, shipped []T
+		unrouted := linq.From([]T{...}).
+			RouteThis(
+				func(item T) any {
+					return item.StatusField
+				},
+				map[any]*[]T{
+					"pending": &pending,
+					"shipped": &shipped,
+				},
+			)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Route iterates over the Iterable once and appends each item to the sink for
+// the value of its given field, as with RouteThis. Items whose field value has
+// no sink are returned. If T is not a struct, or fieldName is not found, this
+// function will panic.
+func (iterable Iterable[T]) Route(fieldName string, sinks map[any]*[]T) []T {
+
+	return iterable.RouteThis(
+		getFieldNameFunc[T](fieldName),
+		sinks,
+	)
+
+	/*
+		var pending, shipped []T
+		unrouted := linq.From([]T{...}).
+			Route("StatusField", map[any]*[]T{
+				"pending": &pending,
+				"shipped": &shipped,
+			})
+	*/
+}
@@ -2,6 +2,7 @@ package weaklinq
 
 import (
 	"iter"
+	"slices"
 	"testing"
 )
 
@@ -152,3 +153,215 @@ func TestDistinct(t *testing.T) {
 		t.Errorf("Expected no item but got %v", item)
 	}
 }
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestDistinctReiteration(t *testing.T) {
+
+	result := From([]int{1, 2, 1, 3}).Distinct()
+
+	first := slices.Collect(result.Seq)
+	second := slices.Collect(result.Seq)
+
+	if !slices.Equal(first, []int{1, 2, 3}) {
+		t.Errorf("Expected [1 2 3] but got %v", first)
+	}
+
+	if !slices.Equal(second, first) {
+		t.Errorf("Expected %v on the second iteration but got %v", first, second)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestPartitionOnThis(t *testing.T) {
+
+	reads := 0
+	source := Iterable[int]{
+		Seq: func(yield func(int) bool) {
+			for i := range 6 {
+				reads++
+				if !yield(i) {
+					return
+				}
+			}
+		},
+	}
+
+	calls := 0
+	even, odd := source.PartitionOnThis(
+		func(item int) bool {
+			calls++
+			return item%2 == 0
+		},
+	)
+
+	if !slices.Equal(even, []int{0, 2, 4}) {
+		t.Errorf("Expected [0 2 4] but got %v", even)
+	}
+
+	if !slices.Equal(odd, []int{1, 3, 5}) {
+		t.Errorf("Expected [1 3 5] but got %v", odd)
+	}
+
+	if reads != 6 || calls != 6 {
+		t.Errorf("Expected 6 reads and 6 calls but got %v and %v", reads, calls)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestPartitionOn(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("generic", func(t *testing.T) {
+
+		testItems := []testStruct{
+			{Id: 1, Name: "Test 1", IsActive: true},
+			{Id: 2, Name: "Test 2", IsActive: false},
+			{Id: 3, Name: "Test 3", IsActive: true},
+		}
+
+		active, inactive := From(testItems).PartitionOn("IsActive")
+
+		if !slices.Equal(active, []testStruct{testItems[0], testItems[2]}) {
+			t.Errorf("Expected first and third items but got %v", active)
+		}
+
+		if !slices.Equal(inactive, []testStruct{testItems[1]}) {
+			t.Errorf("Expected second item but got %v", inactive)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("empty", func(t *testing.T) {
+
+		active, inactive := From([]testStruct{}).PartitionOn("IsActive")
+
+		if len(active) != 0 || len(inactive) != 0 {
+			t.Errorf("Expected no items but got %v and %v", active, inactive)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("bad field name", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		From([]testStruct{{Id: 1}}).PartitionOn("BadFieldName")
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestRouteThis(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("generic", func(t *testing.T) {
+
+		var small, large []int
+		unrouted := From([]int{1, 50, 2, 500, 60}).RouteThis(
+			func(item int) any {
+				switch {
+				case item < 10:
+					return "small"
+				case item < 100:
+					return "large"
+				}
+				return "huge"
+			},
+			map[any]*[]int{
+				"small": &small,
+				"large": &large,
+			},
+		)
+
+		if !slices.Equal(small, []int{1, 2}) {
+			t.Errorf("Expected [1 2] but got %v", small)
+		}
+
+		if !slices.Equal(large, []int{50, 60}) {
+			t.Errorf("Expected [50 60] but got %v", large)
+		}
+
+		if !slices.Equal(unrouted, []int{500}) {
+			t.Errorf("Expected [500] but got %v", unrouted)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("appends to sinks", func(t *testing.T) {
+
+		sink := []int{0}
+		From([]int{1, 2}).RouteThis(func(int) any { return true }, map[any]*[]int{true: &sink})
+
+		if !slices.Equal(sink, []int{0, 1, 2}) {
+			t.Errorf("Expected [0 1 2] but got %v", sink)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("nil sink", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		From([]int{1}).RouteThis(func(int) any { return 1 }, map[any]*[]int{1: nil})
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestRoute(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("generic", func(t *testing.T) {
+
+		testItems := []testStruct{
+			{Id: 1, Name: "A"},
+			{Id: 2, Name: "B"},
+			{Id: 3, Name: "A"},
+			{Id: 4, Name: "C"},
+		}
+
+		var as, bs []testStruct
+		unrouted := From(testItems).Route("Name", map[any]*[]testStruct{"A": &as, "B": &bs})
+
+		if len(as) != 2 || len(bs) != 1 || len(unrouted) != 1 || unrouted[0].Id != 4 {
+			t.Errorf("Expected 2, 1 and 1 items but got %v, %v and %v", as, bs, unrouted)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("bad field name", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		From([]testStruct{{Id: 1}}).Route("BadFieldName", map[any]*[]testStruct{})
+	})
+
+	//----------------------------------------------------------------------------//
+}
@@ -1,22 +1,55 @@
 package weaklinq
 
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+)
+
 //----------------------------------------------------------------------------//
 // Grouping                                                                   //
 //----------------------------------------------------------------------------//
 
 ////////////////////////////////////////////////////////////////////////////////
 
+// conflictPolicy is an enum that represents what a MapIterable set to
+// overwrite does when a key it has already seen comes up again.
+type conflictPolicy int
+
+const (
+	KeepLast conflictPolicy = iota
+	KeepFirst
+	PanicOnConflict
+)
+
+////////////////////////////////////////////////////////////////////////////////
+
 // MapIterable is a specialized iterable that includes a key selector and an
 // item selector. These selectors are stored until the collection is iterated,
 // and then applied to the items. The Overwrite flag determines whether the
 // map value for a given key should be overwritten or appended to. If set to
 // true, the result map will be expected to be a map[K]V, and if set to false,
-// the result map will be expected to be a map[K][]V.
+// the result map will be expected to be a map[K][]V. If reducers are set,
+// the map value for a given key is the result of the reducers instead. Any
+// ThenBy key selectors group the items into further nested levels. If a key
+// level was set by field names, they are kept so that the key can be assigned
+// to a struct key by name. The conflict policy and merge function decide
+// which value a key set to overwrite ends up with, and the having predicates
+// decide which groups are kept.
 type MapIterable[T any] struct {
-	itemIterable Iterable[T]
-	keySelector  func(T) any
-	itemSelector func(T) any
-	overwrite    bool
+	itemIterable      Iterable[T]
+	keySelector       func(T) any
+	keyFieldNames     []string
+	thenKeySelectors  []func(T) any
+	thenKeyFieldNames [][]string
+	itemSelector      func(T) any
+	selectsItems      bool
+	overwrite         bool
+	conflictPolicy    conflictPolicy
+	mergeConflicts    func(existing any, incoming any) any
+	reducers          []Reducer
+	havingPredicates  []func(key any, value any) bool
 }
 
 /////////////////////////////////////////////////////////////////////////////////
@@ -26,6 +59,15 @@ type MapIterable[T any] struct {
 // little use outside of that.
 type DeferredKeyMapIterable[T any] MapIterable[T]
 
+/////////////////////////////////////////////////////////////////////////////////
+
+// KeyValue is a key and its grouped value, as assigned by
+// AndAssignToOrderedSlice.
+type KeyValue[K any, V any] struct {
+	Key   K
+	Value V
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 
 // defaultMapIterable returns a default MapIterable for the given iterable where
@@ -50,6 +92,7 @@ func (iterable Iterable[T]) GroupThis(selector func(T) any) DeferredKeyMapIterab
 
 	mapIterable := defaultMapIterable(iterable)
 	mapIterable.itemSelector = selector
+	mapIterable.selectsItems = true
 
 	return DeferredKeyMapIterable[T](mapIterable)
 
@@ -101,6 +144,7 @@ func (iterable Iterable[T]) GroupListsOfThis(selector func(T) any) DeferredKeyMa
 
 	mapIterable := defaultMapIterable(iterable)
 	mapIterable.itemSelector = selector
+	mapIterable.selectsItems = true
 	mapIterable.overwrite = false
 
 	return DeferredKeyMapIterable[T](mapIterable)
@@ -168,14 +212,19 @@ func (iterable Iterable[T]) GroupByThis(selector func(T) any) MapIterable[T] {
 ////////////////////////////////////////////////////////////////////////////////
 
 // GroupBy returns a new MapIterable where the items are grouped by the given
-// field name. Items with the same key WILL BE OVERWRITTEN as this iterable is
-// iterated. If T is not a struct, or fieldName is not found, this function
-// will panic.
-func (iterable Iterable[T]) GroupBy(fieldName string) MapIterable[T] {
-
-	return iterable.GroupByThis(
-		getFieldNameFunc[T](fieldName),
+// field names. Items with the same key WILL BE OVERWRITTEN as this iterable
+// is iterated. If more than one field name is given, the key is a
+// CompositeKey of their values, which can be assigned to a map keyed by a
+// struct with fields of the same names. If T is not a struct, or any
+// fieldName is not found, this function will panic.
+func (iterable Iterable[T]) GroupBy(fieldNames ...string) MapIterable[T] {
+
+	mapIterable := iterable.GroupByThis(
+		getFieldNamesFunc[T](fieldNames...),
 	)
+	mapIterable.keyFieldNames = fieldNames
+
+	return mapIterable
 
 	/*
 		linq.From([]T{...}).
@@ -209,18 +258,95 @@ func (iterable Iterable[T]) GroupListsByThis(selector func(T) any) MapIterable[T
 ////////////////////////////////////////////////////////////////////////////////
 
 // GroupListsBy returns a new MapIterable where the items are grouped by the
-// given field name. Items with the same key will NOT be overwritten as this
-// iterable is iterated. If T is not a struct, or fieldName is not found, this
-// function will panic.
-func (iterable Iterable[T]) GroupListsBy(fieldName string) MapIterable[T] {
-
-	return iterable.GroupListsByThis(
-		getFieldNameFunc[T](fieldName),
+// given field names. Items with the same key will NOT be overwritten as this
+// iterable is iterated. If more than one field name is given, the key is a
+// CompositeKey of their values, which can be assigned to a map keyed by a
+// struct with fields of the same names. If T is not a struct, or any
+// fieldName is not found, this function will panic.
+func (iterable Iterable[T]) GroupListsBy(fieldNames ...string) MapIterable[T] {
+
+	mapIterable := iterable.GroupListsByThis(
+		getFieldNamesFunc[T](fieldNames...),
 	)
+	mapIterable.keyFieldNames = fieldNames
+
+	return mapIterable
 
 	/*
+		// Single
 		linq.From([]T{...}).
 			GroupListsBy("ItemField")
+
+		// Composite
+		result := make(map[struct{ Year, Month int }][]T)
+		linq.From([]T{...}).
+			GroupListsBy("Year", "Month").
+			AndAssignToMap(&result)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// GroupAdjacentThis returns a new Iterable of Groupings of runs of adjacent
+// items that share the same key, as returned by the given key selector. A new
+// Grouping is yielded each time the key changes, so items with the same key
+// that are not adjacent end up in separate Groupings. Unlike the other
+// grouping functions, only the current run is held in memory, so sorted
+// streams of any length can be grouped. Keys are compared with ==. A free
+// function rather than a method, as a method on Iterable[T] cannot return an
+// Iterable of a type built from T.
+func GroupAdjacentThis[T any](iterable Iterable[T], selector func(T) any) Iterable[Grouping[any, T]] {
+
+	return Iterable[Grouping[any, T]]{
+		Seq: func(yield func(Grouping[any, T]) bool) {
+			var run Grouping[any, T]
+			for item := range iterable.Seq {
+				key := selector(item)
+				if len(run.Values) > 0 && key != run.Key {
+					if !yield(run) {
+						return
+					}
+					run = Grouping[any, T]{}
+				}
+
+				if len(run.Values) == 0 {
+					run.Key = key
+				}
+				run.Values = append(run.Values, item)
+			}
+
+			if len(run.Values) > 0 {
+				yield(run)
+			}
+		},
+	}
+
+	/*
+		linq.GroupAdjacentThis(
+			linq.From([]T{...}),
+			func(item T) any {
+				return item.KeyField
+			},
+		)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// GroupAdjacent returns a new Iterable of Groupings of runs of adjacent items
+// that share the same values of the given field names, as with
+// GroupAdjacentThis. If more than one field name is given, the key is a
+// CompositeKey of their values. If T is not a struct, or any fieldName is not
+// found, this function will panic.
+func GroupAdjacent[T any](iterable Iterable[T], fieldNames ...string) Iterable[Grouping[any, T]] {
+
+	return GroupAdjacentThis(
+		iterable,
+		getFieldNamesFunc[T](fieldNames...),
+	)
+
+	/*
+		linq.GroupAdjacent(linq.From([]T{...}), "KeyField")
 	*/
 }
 
@@ -249,14 +375,18 @@ func (iterable DeferredKeyMapIterable[T]) ByThis(selector func(T) any) MapIterab
 ////////////////////////////////////////////////////////////////////////////////
 
 // By returns a new MapIterable where the items are grouped by the given field
-// name. Callable from other MapIterables. Designed to be used in tandem with
+// names. Callable from other MapIterables. Designed to be used in tandem with
 // the GroupThis or GroupListsOfThis functions. Items with the same key will NOT
-// be overwritten as this iterable is iterated.
-func (iterable DeferredKeyMapIterable[T]) By(fieldName string) MapIterable[T] {
+// be overwritten as this iterable is iterated. If more than one field name is
+// given, the key is a CompositeKey of their values, as with GroupBy.
+func (iterable DeferredKeyMapIterable[T]) By(fieldNames ...string) MapIterable[T] {
 
-	return iterable.ByThis(
-		getFieldNameFunc[T](fieldName),
+	mapIterable := iterable.ByThis(
+		getFieldNamesFunc[T](fieldNames...),
 	)
+	mapIterable.keyFieldNames = fieldNames
+
+	return mapIterable
 
 	/*
 		linq.From([]T{...}).
@@ -264,3 +394,334 @@ func (iterable DeferredKeyMapIterable[T]) By(fieldName string) MapIterable[T] {
 			By("KeyField")
 	*/
 }
+
+////////////////////////////////////////////////////////////////////////////////
+
+// ThenByThis returns a new MapIterable where the items under each key are
+// further grouped by the given key selector, one level deeper. May be called
+// more than once to add more levels. When assigned to a map, the map must be
+// nested one level per key, such as map[K1]map[K2][]V.
+func (iterable MapIterable[T]) ThenByThis(selector func(T) any) MapIterable[T] {
+
+	iterable.thenKeySelectors = append(slices.Clip(iterable.thenKeySelectors), selector)
+	iterable.thenKeyFieldNames = append(slices.Clip(iterable.thenKeyFieldNames), nil)
+	return iterable
+
+	/*
+		linq.From([]T{...}).
+			GroupListsByThis(
+				func(item T) any {
+					return item.KeyField1
+				},
+			).ThenByThis(
+				func(item T) any {
+					return item.KeyField2
+				},
+			)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// ThenBy returns a new MapIterable where the items under each key are further
+// grouped by the given field names, one level deeper. May be called more
+// than once to add more levels. When assigned to a map, the map must be
+// nested one level per key, such as map[K1]map[K2][]V. If more than one field
+// name is given, the key is a CompositeKey of their values, as with GroupBy.
+// If T is not a struct, or any fieldName is not found, this function will
+// panic.
+func (iterable MapIterable[T]) ThenBy(fieldNames ...string) MapIterable[T] {
+
+	mapIterable := iterable.ThenByThis(
+		getFieldNamesFunc[T](fieldNames...),
+	)
+	mapIterable.thenKeyFieldNames[len(mapIterable.thenKeyFieldNames)-1] = fieldNames
+
+	return mapIterable
+
+	/*
+		linq.From([]T{...}).
+			GroupListsBy("KeyField1").
+			ThenBy("KeyField2")
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// OnConflict returns a new MapIterable that uses the given policy when a key
+// comes up more than once while set to overwrite. KeepLast is the default and
+// keeps the last value, KeepFirst keeps the first value, and PanicOnConflict
+// panics with the duplicate key. Clears any merge function set by
+// MergeConflictsThis. Has no effect on lists or aggregates.
+func (iterable MapIterable[T]) OnConflict(policy conflictPolicy) MapIterable[T] {
+
+	iterable.conflictPolicy = policy
+	iterable.mergeConflicts = nil
+	return iterable
+
+	/*
+		linq.From([]T{...}).
+			GroupBy("KeyField").
+			OnConflict(linq.PanicOnConflict)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// MergeConflictsThis returns a new MapIterable that, when a key comes up more
+// than once while set to overwrite, replaces its value with the result of
+// merging the existing value with the incoming one using the given function.
+// Has no effect on lists or aggregates.
+func (iterable MapIterable[T]) MergeConflictsThis(merge func(existing any, incoming any) any) MapIterable[T] {
+
+	iterable.mergeConflicts = merge
+	return iterable
+
+	/*
+		linq.From([]T{...}).
+			Group("ValueField").
+			By("KeyField").
+			MergeConflictsThis(
+				func(existing any, incoming any) any {
+					return existing.(int) + incoming.(int)
+				},
+			)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// resolveConflict returns the value that the given key should end up with when
+// the incoming value arrives for a key that already has the existing value,
+// based on the merge function or conflict policy. The key is the full group
+// key, a CompositeKey of every level if there are ThenBy levels, so that it
+// names the duplicate unambiguously. If the policy is PanicOnConflict, this
+// function will panic.
+func (iterable MapIterable[T]) resolveConflict(key any, existing any, incoming any) any {
+
+	if iterable.mergeConflicts != nil {
+		return iterable.mergeConflicts(existing, incoming)
+	}
+
+	switch iterable.conflictPolicy {
+	case KeepFirst:
+		return existing
+	case PanicOnConflict:
+		panic(fmt.Sprintf("duplicate key %v", key))
+	}
+
+	return incoming
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// HavingThis returns a new MapIterable that only keeps the groups for which
+// the given predicate returns true. The predicate is given each key and its
+// value as yielded by Groups: the last item if set to overwrite, a []any of
+// the items if not, or a []any of the reducer results if there are reducers.
+// Calling HavingThis more than once keeps only the groups that pass every
+// predicate. Groups are filtered once all of the items have been grouped, as
+// the MapIterable is iterated. To filter on a single aggregate by name, use
+// Having.
+func (iterable MapIterable[T]) HavingThis(predicate func(key any, value any) bool) MapIterable[T] {
+
+	iterable.havingPredicates = append(slices.Clip(iterable.havingPredicates), predicate)
+	return iterable
+
+	/*
+		linq.From([]T{...}).
+			GroupBy("CustomerField").
+			Aggregate(linq.Count()).
+			HavingThis(
+				func(key any, value any) bool {
+					return value.([]any)[0].(int) > 5
+				},
+			)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Having returns a new MapIterable that only keeps the groups for which the
+// given predicate returns true, given the result of the reducer with the given
+// name, as set by Aggregate or Reducer.As. Can be chained with HavingThis. If
+// the MapIterable has no reducer with the given name, this function will
+// panic, so Aggregate must be called first.
+func (iterable MapIterable[T]) Having(reducerName string, predicate func(result any) bool) MapIterable[T] {
+
+	index := slices.IndexFunc(iterable.reducers, func(reducer Reducer) bool {
+		return reducer.name == reducerName
+	})
+	if index < 0 {
+		panic(fmt.Sprintf("reducer name '%s' not found in aggregate", reducerName))
+	}
+
+	return iterable.HavingThis(
+		func(_ any, value any) bool {
+			return predicate(value.([]any)[index])
+		},
+	)
+
+	/*
+		linq.From([]T{...}).
+			GroupBy("CustomerField").
+			Aggregate(linq.Count().As("Orders")).
+			Having(
+				"Orders",
+				func(orders any) bool {
+					return orders.(int) > 5
+				},
+			)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// isKept returns whether the given group passes every having predicate.
+func (iterable MapIterable[T]) isKept(key any, value any) bool {
+
+	for _, predicate := range iterable.havingPredicates {
+		if !predicate(key, value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// levelKeys returns the keys of the given item at each level of the
+// MapIterable, starting with the outermost.
+func (iterable MapIterable[T]) levelKeys(item T) []any {
+
+	keys := make([]any, 0, 1+len(iterable.thenKeySelectors))
+	keys = append(keys, iterable.keySelector(item))
+	for _, selector := range iterable.thenKeySelectors {
+		keys = append(keys, selector(item))
+	}
+
+	return keys
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// groupKey returns the key of the given item, or a CompositeKey of its keys at
+// every level if the MapIterable has ThenBy key levels.
+func (iterable MapIterable[T]) groupKey(item T) any {
+
+	if len(iterable.thenKeySelectors) == 0 {
+		return iterable.keySelector(item)
+	}
+
+	return CompositeKey(iterable.levelKeys(item)...)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// mapKey returns the given key at the given level as a value of the given key
+// type. If the key type is a struct and the level was set by field names, the
+// struct is filled from the key by name, one field per field name. Otherwise
+// the key is returned as is.
+func (iterable MapIterable[T]) mapKey(keyType reflect.Type, level int, key any) reflect.Value {
+
+	fieldNames := iterable.keyFieldNames
+	if level > 0 {
+		fieldNames = iterable.thenKeyFieldNames[level-1]
+	}
+
+	reflectKey := reflect.ValueOf(key)
+	if keyType.Kind() != reflect.Struct || len(fieldNames) == 0 || reflectKey.Type() == keyType {
+		return reflectKey
+	}
+
+	parts := splitGroupKey(key, len(fieldNames))
+	structKey := reflect.New(keyType).Elem()
+	for i, fieldName := range fieldNames {
+		field := structKey.FieldByName(fieldName)
+		if !field.IsValid() {
+			panic(fmt.Sprintf("field name '%s' not found in struct %v", fieldName, keyType))
+		}
+		field.Set(convertedValue(fieldName, parts[i], field.Type()))
+	}
+
+	return structKey
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// splitGroupKey returns the keys at each level of a key returned by groupKey.
+func splitGroupKey(key any, levels int) []any {
+
+	if levels == 1 {
+		return []any{key}
+	}
+
+	compositeKey := reflect.ValueOf(key)
+	keys := make([]any, levels)
+	for i := range keys {
+		keys[i] = compositeKey.Index(i).Interface()
+	}
+
+	return keys
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Groups returns an iterator over the keys of the MapIterable and their
+// values, in the order the keys were first seen. If the MapIterable is set
+// to overwrite, each value is the last item under its key. If not, each value
+// is a []any of the items under its key. If it has reducers, each value is a
+// []any of the reducer results. If the MapIterable has ThenBy key levels,
+// each key is a CompositeKey of the keys at every level. Groups that fail a
+// HavingThis predicate are skipped. Nothing is grouped until the iterator is
+// used.
+func (iterable MapIterable[T]) Groups() iter.Seq2[any, any] {
+
+	return func(yield func(any, any) bool) {
+
+		if len(iterable.reducers) > 0 {
+			keys, results := iterable.aggregates()
+			for _, key := range keys {
+				if iterable.isKept(key, results[key]) && !yield(key, results[key]) {
+					return
+				}
+			}
+			return
+		}
+
+		keys := make([]any, 0)
+		values := make(map[any]any)
+		for item := range iterable.itemIterable.Seq {
+
+			key := iterable.groupKey(item)
+			value := iterable.itemSelector(item)
+			existing, exists := values[key]
+			if !exists {
+				keys = append(keys, key)
+			}
+
+			if iterable.overwrite && exists {
+				values[key] = iterable.resolveConflict(key, existing, value)
+			} else if iterable.overwrite {
+				values[key] = value
+			} else if !exists {
+				values[key] = []any{value}
+			} else {
+				values[key] = append(existing.([]any), value)
+			}
+		}
+
+		for _, key := range keys {
+			if iterable.isKept(key, values[key]) && !yield(key, values[key]) {
+				return
+			}
+		}
+	}
+
+	/*
+		for key, value := range linq.From([]T{...}).GroupBy("KeyField").Groups() {
+			...
+		}
+	*/
+}
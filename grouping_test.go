@@ -2,6 +2,7 @@ package weaklinq
 
 import (
 	"iter"
+	"slices"
 	"testing"
 )
 
@@ -98,6 +99,28 @@ func TestGroup(t *testing.T) {
 	})
 
 	//----------------------------------------------------------------------------//
+
+	t.Run("map values", func(t *testing.T) {
+
+		type record struct {
+			Id    int
+			Attrs map[string]string
+		}
+
+		records := []record{
+			{Id: 1, Attrs: map[string]string{"color": "red"}},
+			{Id: 2, Attrs: map[string]string{"color": "blue"}},
+		}
+
+		result := make(map[int]map[string]string)
+		From(records).Group("Attrs").By("Id").AndAssignToMap(&result)
+
+		if result[1]["color"] != "red" || result[2]["color"] != "blue" {
+			t.Errorf("Expected red and blue but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -332,6 +355,151 @@ func TestGroupListsBy(t *testing.T) {
 
 ////////////////////////////////////////////////////////////////////////////////
 
+func TestGroupAdjacentThis(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("generic", func(t *testing.T) {
+
+		keys := make([]any, 0)
+		sizes := make([]int, 0)
+		for grouping := range GroupAdjacentThis(From([]int{1, 1, 2, 3, 3, 3, 1}), func(item int) any { return item }).Seq {
+			keys = append(keys, grouping.Key)
+			sizes = append(sizes, len(grouping.Values))
+		}
+
+		if !slices.Equal(keys, []any{1, 2, 3, 1}) {
+			t.Errorf("Expected [1 2 3 1] but got %v", keys)
+		}
+
+		if !slices.Equal(sizes, []int{2, 1, 3, 1}) {
+			t.Errorf("Expected [2 1 3 1] but got %v", sizes)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("empty", func(t *testing.T) {
+
+		for grouping := range GroupAdjacentThis(From([]int{}), func(item int) any { return item }).Seq {
+			t.Errorf("Expected no groupings but got %v", grouping)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("streaming", func(t *testing.T) {
+
+		reads := 0
+		source := Iterable[int]{
+			Seq: func(yield func(int) bool) {
+				for i := 0; ; i++ {
+					reads++
+					if !yield(i / 3) {
+						return
+					}
+				}
+			},
+		}
+
+		count := 0
+		for grouping := range GroupAdjacentThis(source, func(item int) any { return item }).Seq {
+			if len(grouping.Values) != 3 {
+				t.Errorf("Expected 3 items but got %v", grouping.Values)
+			}
+			count++
+			if count == 2 {
+				break
+			}
+		}
+
+		if reads != 7 {
+			t.Errorf("Expected 7 reads but got %v", reads)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("independent runs", func(t *testing.T) {
+
+		groupings := make([]Grouping[any, int], 0)
+		for grouping := range GroupAdjacentThis(From([]int{1, 1, 2, 2}), func(item int) any { return item }).Seq {
+			groupings = append(groupings, grouping)
+		}
+
+		groupings[0].Values = append(groupings[0].Values, 9)
+		if !slices.Equal(groupings[1].Values, []int{2, 2}) {
+			t.Errorf("Expected [2 2] but got %v", groupings[1].Values)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestGroupAdjacent(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("generic", func(t *testing.T) {
+
+		testItems := []testStruct{
+			{Id: 1, Name: "A"},
+			{Id: 2, Name: "A"},
+			{Id: 3, Name: "B"},
+			{Id: 4, Name: "A"},
+		}
+
+		keys := make([]any, 0)
+		for grouping := range GroupAdjacent(From(testItems), "Name").Seq {
+			keys = append(keys, grouping.Key)
+		}
+
+		if !slices.Equal(keys, []any{"A", "B", "A"}) {
+			t.Errorf("Expected [A B A] but got %v", keys)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("composite", func(t *testing.T) {
+
+		testItems := []testStruct{
+			{Id: 1, Name: "A", IsActive: true},
+			{Id: 2, Name: "A", IsActive: true},
+			{Id: 3, Name: "A", IsActive: false},
+		}
+
+		keys := make([]any, 0)
+		for grouping := range GroupAdjacent(From(testItems), "Name", "IsActive").Seq {
+			keys = append(keys, grouping.Key)
+		}
+
+		if !slices.Equal(keys, []any{CompositeKey("A", true), CompositeKey("A", false)}) {
+			t.Errorf("Expected two composite keys but got %v", keys)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("bad field name", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		for range GroupAdjacent(From([]testStruct{{Id: 1}}), "BadFieldName").Seq {
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
 func TestByThis(t *testing.T) {
 
 	testItems := []testStruct{
@@ -429,3 +597,828 @@ func TestBy(t *testing.T) {
 
 	//----------------------------------------------------------------------------//
 }
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestCompositeGroupKeys(t *testing.T) {
+
+	type entry struct {
+		Year   int
+		Month  int
+		Amount int
+	}
+
+	type yearMonth struct {
+		Year  int
+		Month int
+	}
+
+	entries := []entry{
+		{Year: 2024, Month: 1, Amount: 10},
+		{Year: 2024, Month: 2, Amount: 20},
+		{Year: 2024, Month: 1, Amount: 30},
+		{Year: 2025, Month: 1, Amount: 40},
+	}
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("composite key", func(t *testing.T) {
+
+		result := make(map[any][]entry)
+		From(entries).GroupListsBy("Year", "Month").AndAssignToMap(&result)
+
+		if len(result) != 3 {
+			t.Errorf("Expected 3 groups but got %v", len(result))
+		}
+
+		if group := result[CompositeKey(2024, 1)]; !slices.Equal(group, []entry{entries[0], entries[2]}) {
+			t.Errorf("Expected first and third entries but got %v", group)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("struct key", func(t *testing.T) {
+
+		result := make(map[yearMonth][]entry)
+		From(entries).GroupListsBy("Year", "Month").AndAssignToMap(&result)
+
+		if group := result[yearMonth{Year: 2024, Month: 1}]; !slices.Equal(group, []entry{entries[0], entries[2]}) {
+			t.Errorf("Expected first and third entries but got %v", group)
+		}
+
+		if group := result[yearMonth{Year: 2025, Month: 1}]; !slices.Equal(group, []entry{entries[3]}) {
+			t.Errorf("Expected fourth entry but got %v", group)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("by", func(t *testing.T) {
+
+		result := make(map[yearMonth]int)
+		From(entries).Group("Amount").By("Year", "Month").AndAssignToMap(&result)
+
+		if result[yearMonth{Year: 2024, Month: 1}] != 30 {
+			t.Errorf("Expected 30 but got %v", result[yearMonth{Year: 2024, Month: 1}])
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("aggregate", func(t *testing.T) {
+
+		result := make(map[yearMonth]int)
+		From(entries).GroupBy("Year", "Month").Aggregate(Sum("Amount")).AndAssignToMap(&result)
+
+		if result[yearMonth{Year: 2024, Month: 1}] != 40 || result[yearMonth{Year: 2024, Month: 2}] != 20 {
+			t.Errorf("Expected 40 and 20 but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("then by", func(t *testing.T) {
+
+		type monthAmount struct {
+			Month  int
+			Amount int
+		}
+
+		result := make(map[int]map[monthAmount][]entry)
+		From(entries).GroupListsBy("Year").ThenBy("Month", "Amount").AndAssignToMap(&result)
+
+		if group := result[2024][monthAmount{Month: 2, Amount: 20}]; !slices.Equal(group, []entry{entries[1]}) {
+			t.Errorf("Expected second entry but got %v", group)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("ordered", func(t *testing.T) {
+
+		result := make([]KeyValue[yearMonth, []entry], 0)
+		From(entries).GroupListsBy("Year", "Month").AndAssignToOrderedSlice(&result)
+
+		if len(result) != 3 || result[1].Key != (yearMonth{Year: 2024, Month: 2}) {
+			t.Errorf("Expected second key to be 2024-2 but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("missing struct field", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		result := make(map[struct{ Year int }][]entry)
+		From(entries).GroupListsBy("Year", "Month").AndAssignToMap(&result)
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("no field names", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		From(entries).GroupListsBy()
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestThenBy(t *testing.T) {
+
+	type person struct {
+		Country string
+		City    string
+		Name    string
+		Age     int
+	}
+
+	people := []person{
+		{Country: "CA", City: "Toronto", Name: "Ann", Age: 30},
+		{Country: "CA", City: "Ottawa", Name: "Bob", Age: 40},
+		{Country: "US", City: "Boston", Name: "Cal", Age: 50},
+		{Country: "CA", City: "Toronto", Name: "Dee", Age: 20},
+	}
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("lists", func(t *testing.T) {
+
+		result := make(map[string]map[string][]person)
+		From(people).GroupListsBy("Country").ThenBy("City").AndAssignToMap(&result)
+
+		if len(result) != 2 || len(result["CA"]) != 2 || len(result["US"]) != 1 {
+			t.Fatalf("Expected 2 countries with 2 and 1 cities but got %v", result)
+		}
+
+		if !slices.Equal(result["CA"]["Toronto"], []person{people[0], people[3]}) {
+			t.Errorf("Expected Ann and Dee but got %v", result["CA"]["Toronto"])
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("values", func(t *testing.T) {
+
+		result := make(map[string]map[string]string)
+		From(people).Group("Name").By("Country").ThenBy("City").AndAssignToMap(&result)
+
+		if result["CA"]["Toronto"] != "Dee" || result["US"]["Boston"] != "Cal" {
+			t.Errorf("Expected Dee and Cal but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("three levels", func(t *testing.T) {
+
+		result := make(map[string]map[string]map[int][]string)
+		From(people).
+			GroupListsOf("Name").
+			By("Country").
+			ThenBy("City").
+			ThenByThis(func(item person) any { return item.Age / 10 * 10 }).
+			AndAssignToMap(&result)
+
+		if !slices.Equal(result["CA"]["Toronto"][30], []string{"Ann"}) {
+			t.Errorf("Expected [Ann] but got %v", result["CA"]["Toronto"][30])
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("aggregate", func(t *testing.T) {
+
+		result := make(map[string]map[string]int)
+		From(people).GroupBy("Country").ThenBy("City").Aggregate(Sum("Age")).AndAssignToMap(&result)
+
+		if result["CA"]["Toronto"] != 50 || result["CA"]["Ottawa"] != 40 || result["US"]["Boston"] != 50 {
+			t.Errorf("Expected Toronto 50, Ottawa 40 and Boston 50 but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("branching", func(t *testing.T) {
+
+		byCountry := From(people).GroupListsBy("Country")
+		byCity := byCountry.ThenBy("City")
+		byName := byCountry.ThenBy("Name")
+
+		cities := make(map[string]map[string][]person)
+		byCity.AndAssignToMap(&cities)
+		names := make(map[string]map[string][]person)
+		byName.AndAssignToMap(&names)
+
+		if len(cities["CA"]) != 2 || len(names["CA"]) != 3 {
+			t.Errorf("Expected 2 cities and 3 names but got %v and %v", cities["CA"], names["CA"])
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("too shallow", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		result := make(map[string][]person)
+		From(people).GroupListsBy("Country").ThenBy("City").AndAssignToMap(&result)
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("too deep", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		result := make(map[string]map[string]map[string][]person)
+		From(people).GroupListsBy("Country").ThenBy("City").AndAssignToMap(&result)
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("too deep values", func(t *testing.T) {
+
+		expected := "'result' must be a map nested 2 levels deep, but is map[string]map[string]map[string]weaklinq.person"
+		defer func() {
+			if err := recover(); err != expected {
+				t.Errorf("Expected %q but got %v", expected, err)
+			}
+		}()
+
+		result := make(map[string]map[string]map[string]person)
+		From(people).GroupBy("Country").ThenBy("City").AndAssignToMap(&result)
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("too deep selected values", func(t *testing.T) {
+
+		expected := "'result' must be a map nested 2 levels deep, but is map[string]map[string]map[string]int"
+		defer func() {
+			if err := recover(); err != expected {
+				t.Errorf("Expected %q but got %v", expected, err)
+			}
+		}()
+
+		result := make(map[string]map[string]map[string]int)
+		From(people).Group("Age").By("Country").ThenBy("City").AndAssignToMap(&result)
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("bad field name", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		result := make(map[string]map[string][]person)
+		From(people).GroupListsBy("Country").ThenBy("BadFieldName").AndAssignToMap(&result)
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestOnConflict(t *testing.T) {
+
+	testItems := []testStruct{
+		{Id: 1, Name: "Test 1a"},
+		{Id: 2, Name: "Test 2"},
+		{Id: 1, Name: "Test 1b"},
+	}
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("keep last", func(t *testing.T) {
+
+		result := make(map[int]string)
+		From(testItems).Group("Name").By("Id").OnConflict(KeepLast).AndAssignToMap(&result)
+
+		if result[1] != "Test 1b" {
+			t.Errorf("Expected Test 1b but got %v", result[1])
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("keep first", func(t *testing.T) {
+
+		result := make(map[int]string)
+		From(testItems).Group("Name").By("Id").OnConflict(KeepFirst).AndAssignToMap(&result)
+
+		if result[1] != "Test 1a" || result[2] != "Test 2" {
+			t.Errorf("Expected Test 1a and Test 2 but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("keep first ordered", func(t *testing.T) {
+
+		result := make([]KeyValue[int, string], 0)
+		From(testItems).Group("Name").By("Id").OnConflict(KeepFirst).AndAssignToOrderedSlice(&result)
+
+		if len(result) != 2 || result[0].Value != "Test 1a" {
+			t.Errorf("Expected Test 1a first but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("keep first lookup", func(t *testing.T) {
+
+		result := ToLookup[int, string](From(testItems).Group("Name").By("Id").OnConflict(KeepFirst))
+
+		if !slices.Equal(result.Get(1), []string{"Test 1a"}) {
+			t.Errorf("Expected [Test 1a] but got %v", result.Get(1))
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("existing entry", func(t *testing.T) {
+
+		result := map[int]string{1: "Existing", 2: "Existing", 3: "Existing"}
+		From(testItems).Group("Name").By("Id").OnConflict(KeepFirst).AndAssignToMap(&result)
+
+		if result[1] != "Test 1a" || result[2] != "Test 2" || result[3] != "Existing" {
+			t.Errorf("Expected {1: Test 1a, 2: Test 2, 3: Existing} but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("existing entry no panic", func(t *testing.T) {
+
+		result := map[string]int{"Test 2": 0}
+		From(testItems).Group("Id").By("Name").OnConflict(PanicOnConflict).AndAssignToMap(&result)
+
+		if result["Test 2"] != 2 {
+			t.Errorf("Expected 2 but got %v", result["Test 2"])
+		}
+
+		filtered := map[string]int{"Test 2": 0}
+		From(testItems).
+			Group("Id").
+			By("Name").
+			OnConflict(PanicOnConflict).
+			HavingThis(func(key any, value any) bool { return true }).
+			AndAssignToMap(&filtered)
+
+		if filtered["Test 2"] != 2 {
+			t.Errorf("Expected 2 but got %v", filtered["Test 2"])
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("panic", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err != "duplicate key 1" {
+				t.Errorf("Expected duplicate key panic but got %v", err)
+			}
+		}()
+
+		result := make(map[int]string)
+		From(testItems).Group("Name").By("Id").OnConflict(PanicOnConflict).AndAssignToMap(&result)
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("panic nested", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err != "duplicate key [false 1]" {
+				t.Errorf("Expected duplicate key panic but got %v", err)
+			}
+		}()
+
+		result := make(map[bool]map[int]string)
+		From(testItems).Group("Name").By("IsActive").ThenBy("Id").OnConflict(PanicOnConflict).AndAssignToMap(&result)
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("panic nested groups", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err != "duplicate key [false 1]" {
+				t.Errorf("Expected duplicate key panic but got %v", err)
+			}
+		}()
+
+		for range From(testItems).Group("Name").By("IsActive").ThenBy("Id").OnConflict(PanicOnConflict).Groups() {
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("no conflict", func(t *testing.T) {
+
+		result := make(map[string]int)
+		From(testItems).Group("Id").By("Name").OnConflict(PanicOnConflict).AndAssignToMap(&result)
+
+		if len(result) != 3 {
+			t.Errorf("Expected 3 items but got %v", len(result))
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("lists unaffected", func(t *testing.T) {
+
+		result := make(map[int][]string)
+		From(testItems).GroupListsOf("Name").By("Id").OnConflict(PanicOnConflict).AndAssignToMap(&result)
+
+		if len(result[1]) != 2 {
+			t.Errorf("Expected 2 items but got %v", len(result[1]))
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestMergeConflictsThis(t *testing.T) {
+
+	testItems := []testStruct{
+		{Id: 1, Name: "Test 1a"},
+		{Id: 2, Name: "Test 2"},
+		{Id: 1, Name: "Test 1b"},
+	}
+
+	merge := func(existing any, incoming any) any {
+		return existing.(string) + "+" + incoming.(string)
+	}
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("generic", func(t *testing.T) {
+
+		result := make(map[int]string)
+		From(testItems).Group("Name").By("Id").MergeConflictsThis(merge).AndAssignToMap(&result)
+
+		if result[1] != "Test 1a+Test 1b" || result[2] != "Test 2" {
+			t.Errorf("Expected Test 1a+Test 1b and Test 2 but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("groups", func(t *testing.T) {
+
+		for key, value := range From(testItems).Group("Name").By("Id").MergeConflictsThis(merge).Groups() {
+			if key == 1 && value != "Test 1a+Test 1b" {
+				t.Errorf("Expected Test 1a+Test 1b but got %v", value)
+			}
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("cleared by policy", func(t *testing.T) {
+
+		result := make(map[int]string)
+		From(testItems).Group("Name").By("Id").MergeConflictsThis(merge).OnConflict(KeepFirst).AndAssignToMap(&result)
+
+		if result[1] != "Test 1a" {
+			t.Errorf("Expected Test 1a but got %v", result[1])
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("bad merge result", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		ToLookup[int, string](
+			From(testItems).Group("Name").By("Id").MergeConflictsThis(func(any, any) any { return 5 }),
+		)
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestHavingThis(t *testing.T) {
+
+	testItems := []testStruct{
+		{Id: 1, Name: "Test 1a"},
+		{Id: 2, Name: "Test 2"},
+		{Id: 1, Name: "Test 1b"},
+		{Id: 3, Name: "Test 3a"},
+		{Id: 3, Name: "Test 3b"},
+	}
+
+	moreThanOne := func(key any, value any) bool {
+		return len(value.([]any)) > 1
+	}
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("lists", func(t *testing.T) {
+
+		result := make(map[int][]string)
+		From(testItems).GroupListsOf("Name").By("Id").HavingThis(moreThanOne).AndAssignToMap(&result)
+
+		if len(result) != 2 || len(result[1]) != 2 || len(result[3]) != 2 {
+			t.Errorf("Expected keys 1 and 3 with 2 items each but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("aggregate", func(t *testing.T) {
+
+		result := make(map[int]int)
+		From(testItems).
+			GroupBy("Id").
+			Aggregate(Count()).
+			HavingThis(func(key any, value any) bool { return value.([]any)[0].(int) > 1 }).
+			AndAssignToMap(&result)
+
+		if len(result) != 2 || result[1] != 2 || result[3] != 2 {
+			t.Errorf("Expected {1: 2, 3: 2} but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("values", func(t *testing.T) {
+
+		result := make(map[int]string)
+		From(testItems).
+			Group("Name").
+			By("Id").
+			OnConflict(KeepFirst).
+			HavingThis(func(key any, value any) bool { return key.(int) != 2 }).
+			AndAssignToMap(&result)
+
+		if len(result) != 2 || result[1] != "Test 1a" || result[3] != "Test 3a" {
+			t.Errorf("Expected {1: Test 1a, 3: Test 3a} but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("chained", func(t *testing.T) {
+
+		result := make(map[int][]string)
+		From(testItems).
+			GroupListsOf("Name").
+			By("Id").
+			HavingThis(moreThanOne).
+			HavingThis(func(key any, value any) bool { return key.(int) > 1 }).
+			AndAssignToMap(&result)
+
+		if len(result) != 1 || len(result[3]) != 2 {
+			t.Errorf("Expected only key 3 but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("existing entries", func(t *testing.T) {
+
+		result := map[int][]string{1: {"Existing"}}
+		From(testItems).GroupListsOf("Name").By("Id").HavingThis(moreThanOne).AndAssignToMap(&result)
+
+		if len(result[1]) != 3 || result[1][0] != "Existing" {
+			t.Errorf("Expected [Existing Test 1a Test 1b] but got %v", result[1])
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("nested", func(t *testing.T) {
+
+		result := make(map[int]map[string][]testStruct)
+		From(testItems).
+			GroupListsBy("Id").
+			ThenBy("Name").
+			HavingThis(func(key any, value any) bool { return key == CompositeKey(3, "Test 3b") }).
+			AndAssignToMap(&result)
+
+		if len(result) != 1 || len(result[3]) != 1 || len(result[3]["Test 3b"]) != 1 {
+			t.Errorf("Expected only 3/Test 3b but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("lookup", func(t *testing.T) {
+
+		result := ToLookup[int, string](From(testItems).GroupListsOf("Name").By("Id").HavingThis(moreThanOne))
+
+		if !slices.Equal(result.Keys(), []int{1, 3}) {
+			t.Errorf("Expected [1 3] but got %v", result.Keys())
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("branching", func(t *testing.T) {
+
+		grouped := From(testItems).GroupListsOf("Name").By("Id")
+		filtered := grouped.HavingThis(moreThanOne)
+
+		all := make(map[int][]string)
+		grouped.AndAssignToMap(&all)
+		some := make(map[int][]string)
+		filtered.AndAssignToMap(&some)
+
+		if len(all) != 3 || len(some) != 2 {
+			t.Errorf("Expected 3 and 2 keys but got %v and %v", all, some)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestHaving(t *testing.T) {
+
+	testItems := []testStruct{
+		{Id: 1, Name: "Test 1a"},
+		{Id: 2, Name: "Test 2"},
+		{Id: 1, Name: "Test 1b"},
+		{Id: 3, Name: "Test 3a"},
+		{Id: 3, Name: "Test 3b"},
+	}
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("generic", func(t *testing.T) {
+
+		result := make(map[int]struct {
+			Count int
+			SumId int
+		})
+		From(testItems).
+			GroupBy("Id").
+			Aggregate(Count(), Sum("Id")).
+			Having("SumId", func(sum any) bool { return sum.(int) > 2 }).
+			AndAssignToMap(&result)
+
+		if len(result) != 1 || result[3].Count != 2 || result[3].SumId != 6 {
+			t.Errorf("Expected {3: {2 6}} but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("renamed", func(t *testing.T) {
+
+		result := ToLookup[int, int](
+			From(testItems).
+				GroupBy("Id").
+				Aggregate(Count().As("Items")).
+				Having("Items", func(items any) bool { return items.(int) > 1 }).
+				HavingThis(func(key any, value any) bool { return key.(int) < 3 }),
+		)
+
+		if !slices.Equal(result.Keys(), []int{1}) {
+			t.Errorf("Expected [1] but got %v", result.Keys())
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("bad reducer name", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		From(testItems).GroupBy("Id").Aggregate(Count()).Having("Sum", func(any) bool { return true })
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("no reducers", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		From(testItems).GroupListsOf("Name").By("Id").Having("Count", func(any) bool { return true })
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestGroups(t *testing.T) {
+
+	testItems := []testStruct{
+		{Id: 3, Name: "Test 3a"},
+		{Id: 1, Name: "Test 1"},
+		{Id: 3, Name: "Test 3b"},
+		{Id: 2, Name: "Test 2"},
+	}
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("value", func(t *testing.T) {
+
+		keys := make([]any, 0)
+		values := make([]any, 0)
+		for key, value := range From(testItems).Group("Name").By("Id").Groups() {
+			keys = append(keys, key)
+			values = append(values, value)
+		}
+
+		if !slices.Equal(keys, []any{3, 1, 2}) {
+			t.Errorf("Expected [3 1 2] but got %v", keys)
+		}
+
+		if !slices.Equal(values, []any{"Test 3b", "Test 1", "Test 2"}) {
+			t.Errorf("Expected [Test 3b Test 1 Test 2] but got %v", values)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("list", func(t *testing.T) {
+
+		keys := make([]any, 0)
+		for key, value := range From(testItems).GroupListsOf("Name").By("Id").Groups() {
+			keys = append(keys, key)
+			if key == 3 && !slices.Equal(value.([]any), []any{"Test 3a", "Test 3b"}) {
+				t.Errorf("Expected [Test 3a Test 3b] but got %v", value)
+			}
+		}
+
+		if !slices.Equal(keys, []any{3, 1, 2}) {
+			t.Errorf("Expected [3 1 2] but got %v", keys)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("aggregate", func(t *testing.T) {
+
+		for key, value := range From(testItems).GroupBy("Id").Aggregate(Count()).Groups() {
+			if key == 3 && value.([]any)[0] != 2 {
+				t.Errorf("Expected [2] but got %v", value)
+			}
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("early termination", func(t *testing.T) {
+
+		count := 0
+		for range From(testItems).GroupBy("Id").Groups() {
+			count++
+			break
+		}
+
+		if count != 1 {
+			t.Errorf("Expected 1 group but got %v", count)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+}
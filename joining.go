@@ -1,12 +1,27 @@
 package weaklinq
 
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+	"sync"
+)
+
 //----------------------------------------------------------------------------//
 // Joining                                                                    //
 //----------------------------------------------------------------------------//
 
 ////////////////////////////////////////////////////////////////////////////////
 
-// joinType is an enum that represents the type of join to perform.
+// joinType is an enum that represents the type of join to perform. The order
+// of the joined items is the same for every join type: left items are yielded
+// in their original order, each followed by its matches in their original
+// right-side order. Left items with no match are yielded in place for Left
+// and Full Joins, and for Right and Full Joins the unmatched right items are
+// yielded last, in their original right-side order. Semi and Anti Joins
+// yield each left item at most once, in its original order, with no right
+// item.
 type joinType int
 
 const (
@@ -14,6 +29,20 @@ const (
 	LeftJoin
 	RightJoin
 	FullOuterJoin
+	SemiJoin
+	AntiJoin
+)
+
+////////////////////////////////////////////////////////////////////////////////
+
+// asOfDirection is an enum that represents which right item an as-of join
+// pairs each left item with.
+type asOfDirection int
+
+const (
+	notAsOf asOfDirection = iota
+	asOfPreceding
+	asOfFollowing
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -21,12 +50,30 @@ const (
 // JoinIterable is a specialized iterable that includes a right iterable, a key
 // selector for the left iterable, and a key selector for the right iterable.
 // These selectors are stored until the collection is iterated, and then
-// applied to the items.
+// applied to the items. If the join is a cross join, or a predicate, band
+// bounds or an as-of direction are set, items are matched using those in
+// place of key equality. Equality joins may also set a key normalizer or a
+// hash and equality pair for keys that cannot be compared with ==.
 type JoinIterable[T any] struct {
 	itemIterable     Iterable[T]
 	rightIterable    Iterable[any]
 	keySelector      func(T) any
 	rightKeySelector func(any) any
+	rightKeyChecker  func(any)
+	keyFieldNames    []string
+	predicate        func(T, any) bool
+	lowerKeySelector func(T) any
+	upperKeySelector func(T) any
+	withinDistance   any
+	asOfDirection    asOfDirection
+	asOfTolerance    any
+	byKeySelector    func(T) any
+	rightBySelector  func(any) any
+	assumeSorted     bool
+	keyNormalizer    func(any) any
+	keyHash          func(any) uint64
+	keyEqual         func(any, any) bool
+	cross            bool
 	joinType         joinType
 }
 
@@ -39,12 +86,25 @@ type DeferredJoinIterable[T any] JoinIterable[T]
 
 /////////////////////////////////////////////////////////////////////////////////
 
+// DeferredGroupJoinIterable is a DeferredJoinIterable for a group join, as
+// returned by GroupJoin. It has the same key selector functions, but can only
+// be projected with the AsGroups functions, so each left item is yielded once.
+type DeferredGroupJoinIterable[T any] JoinIterable[T]
+
+/////////////////////////////////////////////////////////////////////////////////
+
 // Pair is a struct that holds a left and right item for use in join operations.
 type Pair[TLeft any, TRight any] struct {
 	Left  TLeft
 	Right TRight
 }
 
+/////////////////////////////////////////////////////////////////////////////////
+
+// OptionalPair is a Pair where both items are Optionals, for use in outer join
+// operations where either side may be missing.
+type OptionalPair[TLeft any, TRight any] = Pair[Optional[TLeft], Optional[TRight]]
+
 ////////////////////////////////////////////////////////////////////////////////
 
 func defaultJoinIterable[T any](iterable Iterable[T], joinIterable Iterable[any]) JoinIterable[T] {
@@ -58,6 +118,567 @@ func defaultJoinIterable[T any](iterable Iterable[T], joinIterable Iterable[any]
 	}
 }
 
+/////////////////////////////////////////////////////////////////////////////////
+
+// joinMatcher returns the positions of the right items that match the given
+// left item, in ascending order. Right items are tracked by their position
+// rather than by their value, so equal right items are kept apart and right
+// items do not need to be usable as map keys.
+type joinMatcher[L any] func(L) []int
+
+/////////////////////////////////////////////////////////////////////////////////
+
+// joinStrategy builds a joinMatcher from the right items of a join, in their
+// original order. It is called each time the join is iterated.
+type joinStrategy[L any, R any] func(rightItems []R) joinMatcher[L]
+
+////////////////////////////////////////////////////////////////////////////////
+
+// hashStrategy returns a joinStrategy that builds a hash table of the right
+// items keyed by rightKeySelector and probes it with the key of each left
+// item.
+func hashStrategy[L any, R any, K comparable](leftKeySelector func(L) K, rightKeySelector func(R) K) joinStrategy[L, R] {
+
+	return func(rightItems []R) joinMatcher[L] {
+
+		rightKeysToPositions := make(map[K][]int)
+		for position, rightItem := range rightItems {
+			rightKey := rightKeySelector(rightItem)
+			rightKeysToPositions[rightKey] = append(rightKeysToPositions[rightKey], position)
+		}
+
+		return func(leftItem L) []int {
+			return rightKeysToPositions[leftKeySelector(leftItem)]
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// comparerStrategy returns a joinStrategy that buckets the right items by the
+// hash of their key and matches each left item with the right items in its
+// bucket whose key is equal to its own, using the given hash and equal
+// functions in place of ==.
+func comparerStrategy[L any, R any](
+	leftKeySelector func(L) any,
+	rightKeySelector func(R) any,
+	hash func(any) uint64,
+	equal func(any, any) bool,
+) joinStrategy[L, R] {
+
+	return func(rightItems []R) joinMatcher[L] {
+
+		rightKeys := make([]any, len(rightItems))
+		hashesToPositions := make(map[uint64][]int)
+		for position, rightItem := range rightItems {
+			rightKey := rightKeySelector(rightItem)
+			rightKeys[position] = rightKey
+			rightHash := hash(rightKey)
+			hashesToPositions[rightHash] = append(hashesToPositions[rightHash], position)
+		}
+
+		return func(leftItem L) []int {
+			leftKey := leftKeySelector(leftItem)
+			var positions []int
+			for _, position := range hashesToPositions[hash(leftKey)] {
+				if equal(leftKey, rightKeys[position]) {
+					positions = append(positions, position)
+				}
+			}
+			return positions
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// nestedLoopStrategy returns a joinStrategy that matches each left item
+// against every right item using the given predicate.
+func nestedLoopStrategy[L any, R any](predicate func(L, R) bool) joinStrategy[L, R] {
+
+	return func(rightItems []R) joinMatcher[L] {
+
+		return func(leftItem L) []int {
+			var positions []int
+			for position, rightItem := range rightItems {
+				if predicate(leftItem, rightItem) {
+					positions = append(positions, position)
+				}
+			}
+			return positions
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// crossStrategy returns a joinStrategy that matches each left item with every
+// right item.
+func crossStrategy[L any, R any]() joinStrategy[L, R] {
+
+	return func(rightItems []R) joinMatcher[L] {
+
+		positions := make([]int, len(rightItems))
+		for position := range positions {
+			positions[position] = position
+		}
+
+		return func(L) []int {
+			return positions
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// bandStrategy returns a joinStrategy that sorts the right items by
+// rightKeySelector and matches each left item with the right items whose key
+// is at least lowerKeySelector and less than upperKeySelector, or no more
+// than upperKeySelector if upperInclusive is set. The keys are compared with
+// compareValues, so they must be numbers, strings or time.Time values.
+func bandStrategy[L any, R any](
+	lowerKeySelector func(L) any,
+	upperKeySelector func(L) any,
+	rightKeySelector func(R) any,
+	upperInclusive bool,
+) joinStrategy[L, R] {
+
+	return func(rightItems []R) joinMatcher[L] {
+
+		rightKeys := make([]any, len(rightItems))
+		sortedPositions := make([]int, len(rightItems))
+		for position, rightItem := range rightItems {
+			rightKeys[position] = rightKeySelector(rightItem)
+			sortedPositions[position] = position
+		}
+
+		slices.SortStableFunc(sortedPositions, func(a int, b int) int {
+			return compareValues(rightKeys[a], rightKeys[b])
+		})
+
+		return func(leftItem L) []int {
+			lowerKey := lowerKeySelector(leftItem)
+			upperKey := upperKeySelector(leftItem)
+
+			start, _ := slices.BinarySearchFunc(sortedPositions, lowerKey, func(position int, target any) int {
+				return compareValues(rightKeys[position], target)
+			})
+
+			var positions []int
+			for _, position := range sortedPositions[start:] {
+				comparison := compareValues(rightKeys[position], upperKey)
+				if comparison > 0 || (comparison == 0 && !upperInclusive) {
+					break
+				}
+				positions = append(positions, position)
+			}
+
+			// Matches are yielded in their original right-side order
+			slices.Sort(positions)
+			return positions
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// asOfStrategy returns a joinStrategy that matches each left item with at most
+// one right item: the one with the nearest key at or before the left key if
+// direction is asOfPreceding, or at or after it if direction is asOfFollowing.
+// Only right items with the same by key as the left item are considered, and
+// if tolerance is not nil, the keys may be no more than tolerance apart. When
+// several right items share the nearest key, the last of them is matched when
+// looking back and the first when looking forward. The keys are compared with
+// compareValues, so they must be numbers, strings or time.Time values.
+func asOfStrategy[L any, R any](
+	leftKeySelector func(L) any,
+	rightKeySelector func(R) any,
+	leftBySelector func(L) any,
+	rightBySelector func(R) any,
+	direction asOfDirection,
+	tolerance any,
+) joinStrategy[L, R] {
+
+	return func(rightItems []R) joinMatcher[L] {
+
+		rightKeys := make([]any, len(rightItems))
+		byKeysToPositions := make(map[any][]int)
+		for position, rightItem := range rightItems {
+			rightKeys[position] = rightKeySelector(rightItem)
+			byKey := rightBySelector(rightItem)
+			byKeysToPositions[byKey] = append(byKeysToPositions[byKey], position)
+		}
+
+		for _, positions := range byKeysToPositions {
+			slices.SortStableFunc(positions, func(a int, b int) int {
+				return compareValues(rightKeys[a], rightKeys[b])
+			})
+		}
+
+		return func(leftItem L) []int {
+			positions := byKeysToPositions[leftBySelector(leftItem)]
+			leftKey := leftKeySelector(leftItem)
+
+			compareToLeft := func(position int, target any) int {
+				return compareValues(rightKeys[position], target)
+			}
+
+			var position int
+			if direction == asOfFollowing {
+				// First right item with a key at or after the left key
+				index, _ := slices.BinarySearchFunc(positions, leftKey, compareToLeft)
+				if index == len(positions) {
+					return nil
+				}
+				position = positions[index]
+
+				if tolerance != nil && compareValues(rightKeys[position], offsetValue(leftKey, tolerance, 1)) > 0 {
+					return nil
+				}
+			} else {
+				// Last right item with a key at or before the left key
+				index, found := slices.BinarySearchFunc(positions, leftKey, compareToLeft)
+				if found {
+					for index+1 < len(positions) && compareValues(rightKeys[positions[index+1]], leftKey) == 0 {
+						index++
+					}
+				} else {
+					index--
+				}
+				if index < 0 {
+					return nil
+				}
+				position = positions[index]
+
+				if tolerance != nil && compareValues(rightKeys[position], offsetValue(leftKey, tolerance, -1)) < 0 {
+					return nil
+				}
+			}
+
+			return []int{position}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// matchJoin joins the left and right iterables by matching each left item
+// with the right items found by the given strategy. The joined items are
+// yielded as left/right pairs of Optionals, where a side is empty if the other
+// side had no match in an outer join. The right items are read each time the
+// result is iterated, so neither side is read until then.
+func matchJoin[L any, R any](
+	left Iterable[L],
+	right Iterable[R],
+	strategy joinStrategy[L, R],
+	joinType joinType,
+) iter.Seq2[Optional[L], Optional[R]] {
+
+	return func(yield func(Optional[L], Optional[R]) bool) {
+		var rightItems []R
+		for rightItem := range right.Seq {
+			rightItems = append(rightItems, rightItem)
+		}
+
+		matcher := strategy(rightItems)
+		matchedRightItems := make([]bool, len(rightItems))
+
+		// Process left items
+		for leftItem := range left.Seq {
+			positions := matcher(leftItem)
+			hasMatch := len(positions) > 0
+
+			if joinType == SemiJoin || joinType == AntiJoin {
+				// Semi or Anti Join - yield left alone based on match existence
+				if hasMatch == (joinType == SemiJoin) {
+					if !yield(Some(leftItem), None[R]()) {
+						return
+					}
+				}
+			} else if hasMatch {
+				// Inner, Left, Right, or Full Join with matches
+				for _, position := range positions {
+					matchedRightItems[position] = true
+					if !yield(Some(leftItem), Some(rightItems[position])) {
+						return
+					}
+				}
+			} else if joinType == LeftJoin || joinType == FullOuterJoin {
+				// Left or Full Join with no match - yield left with no right
+				if !yield(Some(leftItem), None[R]()) {
+					return
+				}
+			}
+		}
+
+		// Handle unmatched right items for Right and Full Join, in the order
+		// they appeared in the right iterable
+		if joinType == RightJoin || joinType == FullOuterJoin {
+			for position, rightItem := range rightItems {
+				if !matchedRightItems[position] {
+					// Yield no left with unmatched right
+					if !yield(None[L](), Some(rightItem)) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// hashJoin joins the left and right iterables where the left key equals the
+// right key, using hashStrategy.
+func hashJoin[L any, R any, K comparable](
+	left Iterable[L],
+	right Iterable[R],
+	leftKeySelector func(L) K,
+	rightKeySelector func(R) K,
+	joinType joinType,
+) iter.Seq2[Optional[L], Optional[R]] {
+
+	return matchJoin(left, right, hashStrategy(leftKeySelector, rightKeySelector), joinType)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// groupJoin joins the left and right iterables like matchJoin, but yields each
+// left item once along with all of its matching right items, in their
+// original right-side order. Left items with no match are yielded with an
+// empty slice for Left, Full and Anti Joins and skipped otherwise, and left
+// items with a match are skipped for Anti Joins.
+func groupJoin[L any, R any](
+	left Iterable[L],
+	right Iterable[R],
+	strategy joinStrategy[L, R],
+	joinType joinType,
+) iter.Seq2[L, []R] {
+
+	return func(yield func(L, []R) bool) {
+		var rightItems []R
+		for rightItem := range right.Seq {
+			rightItems = append(rightItems, rightItem)
+		}
+
+		matcher := strategy(rightItems)
+
+		for leftItem := range left.Seq {
+			positions := matcher(leftItem)
+			if len(positions) == 0 && joinType != LeftJoin && joinType != FullOuterJoin && joinType != AntiJoin {
+				continue
+			}
+
+			if len(positions) > 0 && joinType == AntiJoin {
+				continue
+			}
+
+			matches := make([]R, 0, len(positions))
+			for _, position := range positions {
+				matches = append(matches, rightItems[position])
+			}
+
+			if !yield(leftItem, matches) {
+				return
+			}
+		}
+	}
+}
+
+/////////////////////////////////////////////////////////////////////////////////
+
+// sortedCursor reads items from a pulled iterator one at a time, keeping the
+// key of the current item and checking that the keys never decrease.
+type sortedCursor[T any] struct {
+	next        func() (T, bool)
+	keySelector func(T) any
+	side        string
+	item        T
+	key         any
+	ok          bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// advance moves the cursor to the next item. If the next item has a smaller
+// key than the current item, this function will panic.
+func (cursor *sortedCursor[T]) advance() {
+
+	previousKey, hadPrevious := cursor.key, cursor.ok
+
+	cursor.item, cursor.ok = cursor.next()
+	if !cursor.ok {
+		return
+	}
+
+	cursor.key = cursor.keySelector(cursor.item)
+	if hadPrevious && compareValues(cursor.key, previousKey) < 0 {
+		panic(fmt.Sprintf("merge join %s iterable is not sorted: %v comes after %v", cursor.side, cursor.key, previousKey))
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// run reads and returns the items from the current item onwards whose key
+// equals key, leaving the cursor on the first item with a different key.
+func (cursor *sortedCursor[T]) run(key any) []T {
+
+	var items []T
+	for cursor.ok && compareValues(cursor.key, key) == 0 {
+		items = append(items, cursor.item)
+		cursor.advance()
+	}
+
+	return items
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// mergeGroups walks the left and right iterables in lockstep, both of which
+// must be sorted ascending by key, and yields the run of left items and the
+// run of right items for each distinct key in ascending key order. Either run
+// may be empty. Only the current runs are held in memory. If either iterable
+// turns out not to be sorted, iterating will panic.
+func mergeGroups[L any, R any](
+	left Iterable[L],
+	right Iterable[R],
+	leftKeySelector func(L) any,
+	rightKeySelector func(R) any,
+) iter.Seq2[[]L, []R] {
+
+	return func(yield func([]L, []R) bool) {
+		nextLeft, stopLeft := iter.Pull(left.Seq)
+		defer stopLeft()
+		nextRight, stopRight := iter.Pull(right.Seq)
+		defer stopRight()
+
+		leftCursor := &sortedCursor[L]{next: nextLeft, keySelector: leftKeySelector, side: "left"}
+		rightCursor := &sortedCursor[R]{next: nextRight, keySelector: rightKeySelector, side: "right"}
+		leftCursor.advance()
+		rightCursor.advance()
+
+		for leftCursor.ok || rightCursor.ok {
+			key := leftCursor.key
+			if !leftCursor.ok || (rightCursor.ok && compareValues(rightCursor.key, leftCursor.key) < 0) {
+				key = rightCursor.key
+			}
+
+			if !yield(leftCursor.run(key), rightCursor.run(key)) {
+				return
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// mergeJoin joins the left and right iterables like matchJoin, but walks both
+// sorted iterables in lockstep using mergeGroups rather than holding the right
+// items in memory. The joined items are yielded in ascending key order, so
+// unmatched right items are yielded in place rather than last.
+func mergeJoin[L any, R any](
+	left Iterable[L],
+	right Iterable[R],
+	leftKeySelector func(L) any,
+	rightKeySelector func(R) any,
+	joinType joinType,
+) iter.Seq2[Optional[L], Optional[R]] {
+
+	return func(yield func(Optional[L], Optional[R]) bool) {
+		for leftItems, rightItems := range mergeGroups(left, right, leftKeySelector, rightKeySelector) {
+			hasMatch := len(rightItems) > 0
+
+			if joinType == SemiJoin || joinType == AntiJoin {
+				// Semi or Anti Join - yield left alone based on match existence
+				if hasMatch == (joinType == SemiJoin) {
+					for _, leftItem := range leftItems {
+						if !yield(Some(leftItem), None[R]()) {
+							return
+						}
+					}
+				}
+				continue
+			}
+
+			if len(leftItems) > 0 && hasMatch {
+				// Inner, Left, Right, or Full Join with matches
+				for _, leftItem := range leftItems {
+					for _, rightItem := range rightItems {
+						if !yield(Some(leftItem), Some(rightItem)) {
+							return
+						}
+					}
+				}
+			} else if len(leftItems) > 0 && (joinType == LeftJoin || joinType == FullOuterJoin) {
+				// Left or Full Join with no match - yield left with no right
+				for _, leftItem := range leftItems {
+					if !yield(Some(leftItem), None[R]()) {
+						return
+					}
+				}
+			} else if len(leftItems) == 0 && (joinType == RightJoin || joinType == FullOuterJoin) {
+				// Right or Full Join with no match - yield no left with right
+				for _, rightItem := range rightItems {
+					if !yield(None[L](), Some(rightItem)) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// mergeGroupJoin groups the left and right iterables like groupJoin, but
+// walks both sorted iterables in lockstep using mergeGroups.
+func mergeGroupJoin[L any, R any](
+	left Iterable[L],
+	right Iterable[R],
+	leftKeySelector func(L) any,
+	rightKeySelector func(R) any,
+	joinType joinType,
+) iter.Seq2[L, []R] {
+
+	return func(yield func(L, []R) bool) {
+		for leftItems, rightItems := range mergeGroups(left, right, leftKeySelector, rightKeySelector) {
+			if len(rightItems) == 0 && joinType != LeftJoin && joinType != FullOuterJoin && joinType != AntiJoin {
+				continue
+			}
+
+			if len(rightItems) > 0 && joinType == AntiJoin {
+				continue
+			}
+
+			for _, leftItem := range leftItems {
+				if !yield(leftItem, slices.Clone(rightItems)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// selectJoined returns a new Iterable where the joined left/right pairs are
+// transformed by joinSelector.
+func selectJoined[L any, R any, U any](
+	joined iter.Seq2[Optional[L], Optional[R]],
+	joinSelector func(Optional[L], Optional[R]) U,
+) Iterable[U] {
+
+	return Iterable[U]{
+		Seq: func(yield func(U) bool) {
+			for leftItem, rightItem := range joined {
+				if !yield(joinSelector(leftItem, rightItem)) {
+					return
+				}
+			}
+		},
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 
 // Join returns a new DeferredJoinIterable that will join the items of the given iterable
@@ -106,6 +727,105 @@ func (iterable Iterable[T]) FullOuterJoin(joinIterable Iterable[any]) DeferredJo
 
 ////////////////////////////////////////////////////////////////////////////////
 
+// GroupJoin returns a new DeferredGroupJoinIterable that will perform a left
+// group join on the given iterable, yielding each left item once along with
+// all of its matches through the AsGroups functions.
+func (iterable Iterable[T]) GroupJoin(joinIterable Iterable[any]) DeferredGroupJoinIterable[T] {
+
+	return DeferredGroupJoinIterable[T](iterable.LeftJoin(joinIterable))
+
+	/*
+		linq.From([]T{...}).
+			GroupJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftKeyField").
+			Equals("RightKeyField").
+			AsGroups()
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// SemiJoin returns a new DeferredJoinIterable that will perform a semi join
+// on the given iterable, yielding each left item that has at least one match
+// exactly once, with no right item.
+func (iterable Iterable[T]) SemiJoin(joinIterable Iterable[any]) DeferredJoinIterable[T] {
+
+	defaultIterable := defaultJoinIterable(iterable, joinIterable)
+	defaultIterable.joinType = SemiJoin
+	return DeferredJoinIterable[T](defaultIterable)
+
+	/*
+		linq.From([]T{...}).
+			SemiJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftKeyField").
+			Equals("RightKeyField").
+			AsLeftItems()
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AntiJoin returns a new DeferredJoinIterable that will perform an anti join
+// on the given iterable, yielding each left item that has no match exactly
+// once, with no right item.
+func (iterable Iterable[T]) AntiJoin(joinIterable Iterable[any]) DeferredJoinIterable[T] {
+
+	defaultIterable := defaultJoinIterable(iterable, joinIterable)
+	defaultIterable.joinType = AntiJoin
+	return DeferredJoinIterable[T](defaultIterable)
+
+	/*
+		linq.From([]T{...}).
+			AntiJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftKeyField").
+			Equals("RightKeyField").
+			AsLeftItems()
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AsOfJoin returns a new DeferredJoinIterable that will perform an as-of join
+// on the given iterable, pairing each left item with the right item whose key
+// is nearest at or before its own key. The keys are set using the On and
+// Equals functions, and can be narrowed with the By and Following functions
+// and WithTolerance. As with LeftJoin, left items with no match are kept.
+func (iterable Iterable[T]) AsOfJoin(joinIterable Iterable[any]) DeferredJoinIterable[T] {
+
+	defaultIterable := defaultJoinIterable(iterable, joinIterable)
+	defaultIterable.joinType = LeftJoin
+	defaultIterable.asOfDirection = asOfPreceding
+	return DeferredJoinIterable[T](defaultIterable)
+
+	/*
+		linq.From([]T{...}).
+			AsOfJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftTimeField").
+			Equals("RightTimeField").
+			By("SymbolField")
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// CrossJoin returns a new DeferredJoinIterable that will pair every left item
+// with every right item, in left order and then right order. No keys are
+// needed, and the pairs are only produced as the result is iterated.
+func (iterable Iterable[T]) CrossJoin(joinIterable Iterable[any]) DeferredJoinIterable[T] {
+
+	defaultIterable := defaultJoinIterable(iterable, joinIterable)
+	defaultIterable.cross = true
+	return DeferredJoinIterable[T](defaultIterable)
+
+	/*
+		linq.From([]T{...}).
+			CrossJoin(linq.From([]TRight{...}).AsAny()).
+			AsPairs()
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
 // JoinSlice returns a new DeferredJoinIterable that will join the items of the given slice
 func (iterable Iterable[T]) JoinSlice(joinSlice []T) DeferredJoinIterable[T] {
 
@@ -159,41 +879,122 @@ func (iterable Iterable[T]) FullOuterJoinSlice(joinSlice []T) DeferredJoinIterab
 
 ////////////////////////////////////////////////////////////////////////////////
 
-// OnThis sets the key selector functions for both left and right iterables.
-func (iterable DeferredJoinIterable[T]) OnThis(keySelector func(T) any) DeferredJoinIterable[T] {
-
-	iterable.keySelector = keySelector
-	iterable.rightKeySelector = func(item any) any {
-		return keySelector(item.(T))
-	}
+// GroupJoinSlice returns a new DeferredGroupJoinIterable that will perform a
+// left group join on the given slice.
+func (iterable Iterable[T]) GroupJoinSlice(joinSlice []T) DeferredGroupJoinIterable[T] {
 
-	return iterable
+	return iterable.GroupJoin(From(joinSlice).AsAny())
 
 	/*
 		linq.From([]T{...}).
-			Join(linq.From([]TRight{...}).AsAny()).
-			OnThis(
-				func(left T) any {
-					return ...
-				},
-			)
+			GroupJoinSlice([]TRight{...})
 	*/
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 
-// On sets the key selector functions for both left and right iterables using
-// the given field name.
-func (iterable DeferredJoinIterable[T]) On(fieldName string) DeferredJoinIterable[T] {
+// SemiJoinSlice returns a new DeferredJoinIterable that will perform a semi
+// join on the given slice.
+func (iterable Iterable[T]) SemiJoinSlice(joinSlice []T) DeferredJoinIterable[T] {
 
-	return iterable.OnThis(
-		getFieldNameFunc[T](fieldName),
-	)
+	return iterable.SemiJoin(From(joinSlice).AsAny())
+
+	/*
+		linq.From([]T{...}).
+			SemiJoinSlice([]TRight{...})
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AntiJoinSlice returns a new DeferredJoinIterable that will perform an anti
+// join on the given slice.
+func (iterable Iterable[T]) AntiJoinSlice(joinSlice []T) DeferredJoinIterable[T] {
+
+	return iterable.AntiJoin(From(joinSlice).AsAny())
+
+	/*
+		linq.From([]T{...}).
+			AntiJoinSlice([]TRight{...})
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AsOfJoinSlice returns a new DeferredJoinIterable that will perform an as-of
+// join on the given slice.
+func (iterable Iterable[T]) AsOfJoinSlice(joinSlice []T) DeferredJoinIterable[T] {
+
+	return iterable.AsOfJoin(From(joinSlice).AsAny())
+
+	/*
+		linq.From([]T{...}).
+			AsOfJoinSlice([]TRight{...})
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// CrossJoinSlice returns a new DeferredJoinIterable that will pair every left
+// item with every item of the given slice.
+func (iterable Iterable[T]) CrossJoinSlice(joinSlice []T) DeferredJoinIterable[T] {
+
+	return iterable.CrossJoin(From(joinSlice).AsAny())
+
+	/*
+		linq.From([]T{...}).
+			CrossJoinSlice([]TRight{...})
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// OnThis sets the key selector functions for both left and right iterables.
+func (iterable DeferredJoinIterable[T]) OnThis(keySelector func(T) any) DeferredJoinIterable[T] {
+
+	iterable.keySelector = keySelector
+	iterable.rightKeySelector = func(item any) any {
+		return keySelector(item.(T))
+	}
+	iterable.keyFieldNames = nil
+
+	return iterable
+
+	/*
+		linq.From([]T{...}).
+			Join(linq.From([]TRight{...}).AsAny()).
+			OnThis(
+				func(left T) any {
+					return ...
+				},
+			)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// On sets the key selector functions for both left and right iterables using
+// the given field names. If more than one field name is given, the key is a
+// CompositeKey of the values of all of the fields. The right items are read by
+// field name too, so they do not need to be of type T.
+func (iterable DeferredJoinIterable[T]) On(fieldNames ...string) DeferredJoinIterable[T] {
+
+	iterable = iterable.OnThis(
+		getFieldNamesFunc[T](fieldNames...),
+	)
+	iterable.rightKeySelector = getFieldNamesFunc[any](fieldNames...)
+	iterable.keyFieldNames = fieldNames
+
+	return iterable
 
 	/*
 		linq.From([]T{...}).
 			Join(linq.From([]TRight{...}).AsAny()).
 			On("KeyField")
+
+		linq.From([]T{...}).
+			Join(linq.From([]TRight{...}).AsAny()).
+			On("KeyField1", "KeyField2")
 	*/
 }
 
@@ -203,6 +1004,7 @@ func (iterable DeferredJoinIterable[T]) On(fieldName string) DeferredJoinIterabl
 func (iterable DeferredJoinIterable[T]) EqualsThis(rightKeySelector func(any) any) DeferredJoinIterable[T] {
 
 	iterable.rightKeySelector = rightKeySelector
+	iterable.rightKeyChecker = nil
 
 	return iterable
 
@@ -220,112 +1022,1280 @@ func (iterable DeferredJoinIterable[T]) EqualsThis(rightKeySelector func(any) an
 
 ////////////////////////////////////////////////////////////////////////////////
 
-// Equals sets the right key selector function using the given field name.
-func (iterable DeferredJoinIterable[T]) Equals(fieldName string) DeferredJoinIterable[T] {
+// Equals sets the right key selector function using the given field names.
+// If the left key was set using On, the number of field names must match, and
+// each right field must have the same type as the left field in the same
+// position, unless either of them is an interface or a key normalizer or
+// comparer is set. If there is an arity mismatch this function will panic. The
+// right items are only known as any, so their field types are checked once per
+// right item type as the join is iterated, and if there is a type mismatch
+// iterating will panic.
+func (iterable DeferredJoinIterable[T]) Equals(fieldNames ...string) DeferredJoinIterable[T] {
+
+	if len(iterable.keyFieldNames) > 0 && len(iterable.keyFieldNames) != len(fieldNames) {
+		panic(fmt.Sprintf("join key has %d left fields but %d right fields", len(iterable.keyFieldNames), len(fieldNames)))
+	}
 
-	return iterable.EqualsThis(
-		getFieldNameFunc[any](fieldName),
-	)
+	keyFieldNames := iterable.keyFieldNames
+	iterable = iterable.EqualsThis(getFieldNamesFunc[any](fieldNames...))
+	iterable.keyFieldNames = keyFieldNames
+
+	if leftFieldTypes := getFieldTypes[T](keyFieldNames...); len(keyFieldNames) > 0 && leftFieldTypes != nil {
+		iterable.rightKeyChecker = joinFieldTypeChecker(keyFieldNames, leftFieldTypes, fieldNames)
+	}
+
+	return iterable
 
 	/*
 		linq.From([]T{...}).
 			Join(linq.From([]TRight{...}).AsAny()).
 			On("LeftKeyField").
 			Equals("RightKeyField")
+
+		linq.From([]T{...}).
+			Join(linq.From([]TRight{...}).AsAny()).
+			On("LeftKeyField1", "LeftKeyField2").
+			Equals("RightKeyField1", "RightKeyField2")
 	*/
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 
-// AsThis projects the joined items using the given joinSelector function.
-func (iterable DeferredJoinIterable[T]) AsThis(joinSelector func(T, any) any) Iterable[any] {
+// joinFieldTypeChecker returns a function that checks the types of the given
+// right field names in a right item against the given left field types. Each
+// right item type is checked only the first time it is seen. If a right field
+// is not found, or has a different type to the left field in the same
+// position, the returned function will panic.
+func joinFieldTypeChecker(leftFieldNames []string, leftFieldTypes []reflect.Type, rightFieldNames []string) func(any) {
 
-	rightKeysToRightItems := make(map[any][]any)
-	for rightItem := range iterable.rightIterable.Seq {
-		rightKey := iterable.rightKeySelector(rightItem)
-		rightKeysToRightItems[rightKey] = append(rightKeysToRightItems[rightKey], rightItem)
-	}
+	var checkedTypes sync.Map
 
-	return Iterable[any]{
-		Seq: func(yield func(any) bool) {
-			matchedRightItems := make(map[any]bool)
+	return func(item any) {
 
-			// Process left items
-			iterable.itemIterable.Seq(func(leftItem T) bool {
-				leftKey := iterable.keySelector(leftItem)
-				rightItems, hasMatch := rightKeysToRightItems[leftKey]
+		itemType := reflect.TypeOf(item)
+		if _, checked := checkedTypes.Load(itemType); checked || itemType == nil {
+			return
+		}
 
-				if hasMatch {
-					// Inner, Left, Right, or Full Join with matches
-					for _, rightItem := range rightItems {
-						matchedRightItems[rightItem] = true
-						result := joinSelector(leftItem, rightItem)
-						if !yield(result) {
-							return false
-						}
-					}
-				} else if iterable.joinType == LeftJoin || iterable.joinType == FullOuterJoin {
-					// Left or Full Join with no match - yield left with nil right
-					result := joinSelector(leftItem, nil)
-					if !yield(result) {
-						return false
-					}
-				}
+		structType := itemType
+		if structType.Kind() == reflect.Pointer {
+			structType = structType.Elem()
+		}
 
-				return true
-			})
+		if structType.Kind() != reflect.Struct {
+			panic(fmt.Sprintf("item is not a struct or pointer to struct: %v", itemType))
+		}
 
-			// Handle unmatched right items for Right and Full Join
-			if iterable.joinType == RightJoin || iterable.joinType == FullOuterJoin {
-				for _, rightItems := range rightKeysToRightItems {
-					for _, rightItem := range rightItems {
-						if !matchedRightItems[rightItem] {
-							// Yield nil left with unmatched right
-							var zeroLeft T
-							result := joinSelector(zeroLeft, rightItem)
-							if !yield(result) {
-								return
-							}
-						}
-					}
-				}
+		for i, fieldName := range rightFieldNames {
+			field, ok := structType.FieldByName(fieldName)
+			if !ok {
+				panic(fmt.Sprintf("field name '%s' not found in struct %v", fieldName, itemType))
 			}
-		},
+
+			if field.Type.Kind() == reflect.Interface || leftFieldTypes[i].Kind() == reflect.Interface {
+				continue
+			}
+
+			if field.Type != leftFieldTypes[i] {
+				panic(fmt.Sprintf("join key field '%s' is %v, but left key field '%s' is %v",
+					fieldName, field.Type, leftFieldNames[i], leftFieldTypes[i]))
+			}
+		}
+
+		checkedTypes.Store(itemType, true)
 	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// JoinWhere sets a predicate that decides whether a left and right item match,
+// in place of key equality. Every left item is compared with every right
+// item, so this should be used when the match cannot be expressed with keys.
+func (iterable DeferredJoinIterable[T]) JoinWhere(predicate func(T, any) bool) DeferredJoinIterable[T] {
+
+	iterable.predicate = predicate
+
+	return iterable
 
 	/*
 		linq.From([]T{...}).
 			Join(linq.From([]TRight{...}).AsAny()).
-			On("LeftKeyField").
-			Equals("RightKeyField").
-			AsThis(
-				func(left T, right any) any {
-					return ...
+			JoinWhere(
+				func(left T, right any) bool {
+					return left.Start <= right.(TRight).Ts && right.(TRight).Ts < left.End
 				},
 			)
 	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// BetweenThis sets the join to match each left item with the right items whose
+// right key is at least the lower key and less than the upper key, in place of
+// key equality. The right key is set using the Equals functions. The right
+// items are sorted by key once per iteration, so the keys must all be numbers,
+// strings or time.Time values of the same type, or iterating will panic.
+func (iterable DeferredJoinIterable[T]) BetweenThis(lowerKeySelector func(T) any, upperKeySelector func(T) any) DeferredJoinIterable[T] {
+
+	iterable.lowerKeySelector = lowerKeySelector
+	iterable.upperKeySelector = upperKeySelector
+	iterable.withinDistance = nil
+
+	return iterable
 
+	/*
+		linq.From([]T{...}).
+			Join(linq.From([]TRight{...}).AsAny()).
+			BetweenThis(
+				func(left T) any {
+					return left.Start
+				},
+				func(left T) any {
+					return left.End
+				},
+			).
+			Equals("RightKeyField")
+	*/
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 
-// AsPairs projects the joined items as Pair structs.
-func (iterable DeferredJoinIterable[T]) AsPairs() Iterable[any] {
+// Between sets the join to match each left item with the right items whose
+// right key is at least the value of lowerFieldName and less than the value of
+// upperFieldName. If T is not a struct, or either field name is not found,
+// iterating will panic.
+func (iterable DeferredJoinIterable[T]) Between(lowerFieldName string, upperFieldName string) DeferredJoinIterable[T] {
 
-	return iterable.AsThis(
-		func(left T, right any) any {
-			return Pair[T, any]{
-				Left:  left,
-				Right: right,
-			}
-		},
+	return iterable.BetweenThis(
+		getFieldNameFunc[T](lowerFieldName),
+		getFieldNameFunc[T](upperFieldName),
 	)
 
 	/*
 		linq.From([]T{...}).
 			Join(linq.From([]TRight{...}).AsAny()).
+			Between("StartField", "EndField").
+			Equals("RightKeyField")
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Within sets the join to match each left item with the right items whose
+// right key is no more than distance away from its left key, in place of key
+// equality. The keys are set using the On and Equals functions. Numeric keys
+// take a numeric distance, which must be whole for integer keys, and time.Time
+// keys take a time.Duration. The distance must not be negative, but may be
+// larger than the key type can hold. The right items are sorted by key once
+// per iteration, as with Between.
+func (iterable DeferredJoinIterable[T]) Within(distance any) DeferredJoinIterable[T] {
+
+	iterable.lowerKeySelector = nil
+	iterable.upperKeySelector = nil
+	iterable.withinDistance = distance
+
+	return iterable
+
+	/*
+		linq.From([]T{...}).
+			Join(linq.From([]TRight{...}).AsAny()).
+			On("LeftTimeField").
+			Equals("RightTimeField").
+			Within(5 * time.Second)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// ByThis sets the key selector functions that an as-of join must match
+// exactly, in addition to finding the nearest key. Has no effect on other
+// joins.
+func (iterable DeferredJoinIterable[T]) ByThis(keySelector func(T) any, rightKeySelector func(any) any) DeferredJoinIterable[T] {
+
+	iterable.byKeySelector = keySelector
+	iterable.rightBySelector = rightKeySelector
+
+	return iterable
+
+	/*
+		linq.From([]T{...}).
+			AsOfJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftTimeField").
+			Equals("RightTimeField").
+			ByThis(
+				func(left T) any {
+					return left.SymbolField
+				},
+				func(right any) any {
+					return right.(TRight).SymbolField
+				},
+			)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// By sets the fields that an as-of join must match exactly, in addition to
+// finding the nearest key. The same field names are used for both sides, and
+// if more than one is given, a CompositeKey of their values is matched. Has
+// no effect on other joins.
+func (iterable DeferredJoinIterable[T]) By(fieldNames ...string) DeferredJoinIterable[T] {
+
+	return iterable.ByThis(
+		getFieldNamesFunc[T](fieldNames...),
+		getFieldNamesFunc[any](fieldNames...),
+	)
+
+	/*
+		linq.From([]T{...}).
+			AsOfJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftTimeField").
+			Equals("RightTimeField").
+			By("SymbolField")
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Following sets an as-of join to pair each left item with the right item
+// whose key is nearest at or after its own key, rather than at or before it.
+// Has no effect on other joins.
+func (iterable DeferredJoinIterable[T]) Following() DeferredJoinIterable[T] {
+
+	if iterable.asOfDirection != notAsOf {
+		iterable.asOfDirection = asOfFollowing
+	}
+
+	return iterable
+
+	/*
+		linq.From([]T{...}).
+			AsOfJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftTimeField").
+			Equals("RightTimeField").
+			Following()
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// WithTolerance limits an as-of join to right items whose key is no more than
+// tolerance away from the left key. Numeric keys take a numeric tolerance,
+// which must be whole for integer keys, and time.Time keys take a
+// time.Duration. The tolerance must not be negative, but may be larger than
+// the key type can hold.
+func (iterable DeferredJoinIterable[T]) WithTolerance(tolerance any) DeferredJoinIterable[T] {
+
+	iterable.asOfTolerance = tolerance
+
+	return iterable
+
+	/*
+		linq.From([]T{...}).
+			AsOfJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftTimeField").
+			Equals("RightTimeField").
+			WithTolerance(time.Minute)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AssumingSorted sets the join to walk the left and right iterables in
+// lockstep as a merge join, rather than holding all of the right items in
+// memory. Both iterables must be sorted ascending by key, and only the items
+// sharing the current key are held in memory. The keys are compared with each
+// other, so they must be numbers, strings or time.Time values of the same
+// type. The joined items are yielded in key order, with unmatched right items
+// in place rather than last. If either iterable turns out not to be sorted,
+// iterating will panic. Has no effect on cross, JoinWhere, Between, Within or
+// as-of joins.
+func (iterable DeferredJoinIterable[T]) AssumingSorted() DeferredJoinIterable[T] {
+
+	iterable.assumeSorted = true
+
+	return iterable
+
+	/*
+		linq.From([]T{...}).
+			FullOuterJoin(linq.From([]TRight{...}).AsAny()).
 			On("LeftKeyField").
 			Equals("RightKeyField").
-			AsPairs()
+			AssumingSorted()
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// WithKeyNormalizer sets an equality join to pass the left and right keys
+// through the given normalizer before comparing them, such as lowercasing
+// strings for a case-insensitive join, rounding floats, or turning slices
+// into strings. The normalized keys must be usable as map keys, unless
+// WithKeyComparer is also set. Has no effect on cross, JoinWhere, Between,
+// Within or as-of joins.
+func (iterable DeferredJoinIterable[T]) WithKeyNormalizer(normalizer func(any) any) DeferredJoinIterable[T] {
+
+	iterable.keyNormalizer = normalizer
+
+	return iterable
+
+	/*
+		linq.From([]T{...}).
+			Join(linq.From([]TRight{...}).AsAny()).
+			On("LeftNameField").
+			Equals("RightNameField").
+			WithKeyNormalizer(func(key any) any { return strings.ToLower(key.(string)) })
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// WithKeyComparer sets an equality join to match keys using the given hash
+// and equal functions in place of ==, so keys such as slices or maps that
+// cannot be used as map keys can still be joined on. Keys that are equal must
+// have the same hash. Cannot be combined with AssumingSorted, which is
+// ignored. Has no effect on cross, JoinWhere, Between, Within or as-of joins.
+func (iterable DeferredJoinIterable[T]) WithKeyComparer(hash func(any) uint64, equal func(any, any) bool) DeferredJoinIterable[T] {
+
+	if hash == nil || equal == nil {
+		panic("WithKeyComparer requires both a hash and an equal function")
+	}
+
+	iterable.keyHash = hash
+	iterable.keyEqual = equal
+
+	return iterable
+
+	/*
+		linq.From([]T{...}).
+			Join(linq.From([]TRight{...}).AsAny()).
+			On("LeftTagsField").
+			Equals("RightTagsField").
+			WithKeyComparer(hashTags, equalTags)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// checkedRightKeySelector returns the right key selector, checking the right
+// field types set by Equals against the left ones before each key is
+// selected. A key normalizer or comparer may join keys of different types, so
+// the types are not checked if either is set.
+func (iterable DeferredJoinIterable[T]) checkedRightKeySelector() func(any) any {
+
+	rightKeySelector := iterable.rightKeySelector
+	check := iterable.rightKeyChecker
+	if check == nil || iterable.keyNormalizer != nil || iterable.keyEqual != nil {
+		return rightKeySelector
+	}
+
+	return func(item any) any {
+		check(item)
+		return rightKeySelector(item)
+	}
+}
+
+////////
+
+// equalityKeySelectors returns the left and right key selectors of an
+// equality join, with the key normalizer applied if one has been set.
+func (iterable DeferredJoinIterable[T]) equalityKeySelectors() (func(T) any, func(any) any) {
+
+	keySelector := iterable.keySelector
+	rightKeySelector := iterable.checkedRightKeySelector()
+	normalizer := iterable.keyNormalizer
+	if normalizer == nil {
+		return keySelector, rightKeySelector
+	}
+
+	return func(item T) any { return normalizer(keySelector(item)) },
+		func(item any) any { return normalizer(rightKeySelector(item)) }
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// strategy returns the joinStrategy used to match the left and right items,
+// based on which of the matching options have been set.
+func (iterable DeferredJoinIterable[T]) strategy() joinStrategy[T, any] {
+
+	switch {
+	case iterable.cross:
+		return crossStrategy[T, any]()
+
+	case iterable.predicate != nil:
+		return nestedLoopStrategy(iterable.predicate)
+
+	case iterable.asOfDirection != notAsOf:
+		leftBySelector := iterable.byKeySelector
+		rightBySelector := iterable.rightBySelector
+		if leftBySelector == nil || rightBySelector == nil {
+			leftBySelector = func(T) any { return nil }
+			rightBySelector = func(any) any { return nil }
+		}
+		return asOfStrategy(
+			iterable.keySelector,
+			iterable.checkedRightKeySelector(),
+			leftBySelector,
+			rightBySelector,
+			iterable.asOfDirection,
+			iterable.asOfTolerance,
+		)
+
+	case iterable.withinDistance != nil:
+		keySelector := iterable.keySelector
+		distance := iterable.withinDistance
+		return bandStrategy(
+			func(item T) any { return offsetValue(keySelector(item), distance, -1) },
+			func(item T) any { return offsetValue(keySelector(item), distance, 1) },
+			iterable.checkedRightKeySelector(),
+			true,
+		)
+
+	case iterable.lowerKeySelector != nil:
+		return bandStrategy(
+			iterable.lowerKeySelector,
+			iterable.upperKeySelector,
+			iterable.rightKeySelector,
+			false,
+		)
+	}
+
+	keySelector, rightKeySelector := iterable.equalityKeySelectors()
+	if iterable.keyEqual != nil {
+		return comparerStrategy(keySelector, rightKeySelector, iterable.keyHash, iterable.keyEqual)
+	}
+
+	return hashStrategy(keySelector, rightKeySelector)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// usesMergeJoin returns whether the join is an equality join that has been set
+// to assume its iterables are sorted.
+func (iterable DeferredJoinIterable[T]) usesMergeJoin() bool {
+
+	return iterable.assumeSorted &&
+		!iterable.cross &&
+		iterable.keyEqual == nil &&
+		iterable.predicate == nil &&
+		iterable.lowerKeySelector == nil &&
+		iterable.withinDistance == nil &&
+		iterable.asOfDirection == notAsOf
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// joined returns the joined items as left/right pairs of Optionals.
+func (iterable DeferredJoinIterable[T]) joined() iter.Seq2[Optional[T], Optional[any]] {
+
+	if iterable.usesMergeJoin() {
+		keySelector, rightKeySelector := iterable.equalityKeySelectors()
+		return mergeJoin(
+			iterable.itemIterable,
+			iterable.rightIterable,
+			keySelector,
+			rightKeySelector,
+			iterable.joinType,
+		)
+	}
+
+	return matchJoin(
+		iterable.itemIterable,
+		iterable.rightIterable,
+		iterable.strategy(),
+		iterable.joinType,
+	)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AsThis projects the joined items using the given joinSelector function.
+// In outer joins, a missing right item is passed as nil and a missing left
+// item is passed as the zero value of T. Use AsOptionalThis to tell a missing
+// item apart from a zero-valued one.
+func (iterable DeferredJoinIterable[T]) AsThis(joinSelector func(T, any) any) Iterable[any] {
+
+	return iterable.AsOptionalThis(
+		func(left Optional[T], right Optional[any]) any {
+			return joinSelector(left.value, right.value)
+		},
+	)
+
+	/*
+		linq.From([]T{...}).
+			Join(linq.From([]TRight{...}).AsAny()).
+			On("LeftKeyField").
+			Equals("RightKeyField").
+			AsThis(
+				func(left T, right any) any {
+					return ...
+				},
+			)
+	*/
+
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AsOptionalThis projects the joined items using the given joinSelector
+// function. Each side is passed as an Optional, which is empty when that side
+// had no match in an outer join.
+func (iterable DeferredJoinIterable[T]) AsOptionalThis(joinSelector func(Optional[T], Optional[any]) any) Iterable[any] {
+
+	return selectJoined(iterable.joined(), joinSelector)
+
+	/*
+		linq.From([]T{...}).
+			FullOuterJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftKeyField").
+			Equals("RightKeyField").
+			AsOptionalThis(
+				func(left linq.Optional[T], right linq.Optional[any]) any {
+					return ...
+				},
+			)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AsPairs projects the joined items as Pair structs.
+func (iterable DeferredJoinIterable[T]) AsPairs() Iterable[any] {
+
+	return iterable.AsThis(
+		func(left T, right any) any {
+			return Pair[T, any]{
+				Left:  left,
+				Right: right,
+			}
+		},
+	)
+
+	/*
+		linq.From([]T{...}).
+			Join(linq.From([]TRight{...}).AsAny()).
+			On("LeftKeyField").
+			Equals("RightKeyField").
+			AsPairs()
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AsOptionalPairs projects the joined items as OptionalPair structs, where
+// each side is empty when it had no match in an outer join.
+func (iterable DeferredJoinIterable[T]) AsOptionalPairs() Iterable[any] {
+
+	return iterable.AsOptionalThis(
+		func(left Optional[T], right Optional[any]) any {
+			return OptionalPair[T, any]{
+				Left:  left,
+				Right: right,
+			}
+		},
+	)
+
+	/*
+		linq.From([]T{...}).
+			FullOuterJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftKeyField").
+			Equals("RightKeyField").
+			AsOptionalPairs()
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AsLeftItems projects the joined items as just their left items. Most useful
+// with Semi and Anti Joins, where each left item is yielded at most once. In
+// Right and Full Joins, unmatched right items are skipped.
+func (iterable DeferredJoinIterable[T]) AsLeftItems() Iterable[T] {
+
+	joined := iterable.joined()
+
+	return Iterable[T]{
+		Seq: func(yield func(T) bool) {
+			for leftItem := range joined {
+				if !leftItem.HasValue() {
+					continue
+				}
+				if !yield(leftItem.value) {
+					return
+				}
+			}
+		},
+	}
+
+	/*
+		linq.From([]T{...}).
+			SemiJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftKeyField").
+			Equals("RightKeyField").
+			AsLeftItems()
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AsGroupsThis projects each left item along with a slice of all of its
+// matching right items using the given groupSelector function. Left items
+// with no match are passed an empty slice for Left, Full and Anti Joins and
+// skipped otherwise. Unmatched right items are never yielded, as there is no
+// left item to group them under.
+func (iterable DeferredJoinIterable[T]) AsGroupsThis(groupSelector func(T, []any) any) Iterable[any] {
+
+	var grouped iter.Seq2[T, []any]
+	if iterable.usesMergeJoin() {
+		keySelector, rightKeySelector := iterable.equalityKeySelectors()
+		grouped = mergeGroupJoin(
+			iterable.itemIterable,
+			iterable.rightIterable,
+			keySelector,
+			rightKeySelector,
+			iterable.joinType,
+		)
+	} else {
+		grouped = groupJoin(
+			iterable.itemIterable,
+			iterable.rightIterable,
+			iterable.strategy(),
+			iterable.joinType,
+		)
+	}
+
+	return Iterable[any]{
+		Seq: func(yield func(any) bool) {
+			for leftItem, rightItems := range grouped {
+				if !yield(groupSelector(leftItem, rightItems)) {
+					return
+				}
+			}
+		},
+	}
+
+	/*
+		linq.From([]T{...}).
+			LeftJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftKeyField").
+			Equals("RightKeyField").
+			AsGroupsThis(
+				func(left T, rights []any) any {
+					return ...
+				},
+			)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AsGroups projects each left item along with a slice of all of its matching
+// right items as Pair structs.
+func (iterable DeferredJoinIterable[T]) AsGroups() Iterable[any] {
+
+	return iterable.AsGroupsThis(
+		func(left T, rights []any) any {
+			return Pair[T, []any]{
+				Left:  left,
+				Right: rights,
+			}
+		},
+	)
+
+	/*
+		linq.From([]T{...}).
+			LeftJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftKeyField").
+			Equals("RightKeyField").
+			AsGroups()
+	*/
+}
+
+//----------------------------------------------------------------------------//
+// Group Joining                                                              //
+//----------------------------------------------------------------------------//
+
+////////////////////////////////////////////////////////////////////////////////
+
+// OnThis sets the key selector functions for both left and right iterables.
+func (iterable DeferredGroupJoinIterable[T]) OnThis(keySelector func(T) any) DeferredGroupJoinIterable[T] {
+
+	return DeferredGroupJoinIterable[T](DeferredJoinIterable[T](iterable).OnThis(keySelector))
+
+	/*
+		linq.From([]T{...}).
+			GroupJoin(linq.From([]TRight{...}).AsAny()).
+			OnThis(
+				func(left T) any {
+					return ...
+				},
+			)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// On sets the key selector functions for both left and right iterables using
+// the given field names, as with DeferredJoinIterable.On.
+func (iterable DeferredGroupJoinIterable[T]) On(fieldNames ...string) DeferredGroupJoinIterable[T] {
+
+	return DeferredGroupJoinIterable[T](DeferredJoinIterable[T](iterable).On(fieldNames...))
+
+	/*
+		linq.From([]T{...}).
+			GroupJoin(linq.From([]TRight{...}).AsAny()).
+			On("KeyField")
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// EqualsThis sets the right key selector function.
+func (iterable DeferredGroupJoinIterable[T]) EqualsThis(rightKeySelector func(any) any) DeferredGroupJoinIterable[T] {
+
+	return DeferredGroupJoinIterable[T](DeferredJoinIterable[T](iterable).EqualsThis(rightKeySelector))
+
+	/*
+		linq.From([]T{...}).
+			GroupJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftKeyField").
+			EqualsThis(
+				func(right any) any {
+					return ...
+				},
+			)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Equals sets the right key selector function using the given field names,
+// as with DeferredJoinIterable.Equals.
+func (iterable DeferredGroupJoinIterable[T]) Equals(fieldNames ...string) DeferredGroupJoinIterable[T] {
+
+	return DeferredGroupJoinIterable[T](DeferredJoinIterable[T](iterable).Equals(fieldNames...))
+
+	/*
+		linq.From([]T{...}).
+			GroupJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftKeyField").
+			Equals("RightKeyField")
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// JoinWhere sets a predicate that decides whether a left and right item match,
+// in place of key equality, as with DeferredJoinIterable.JoinWhere.
+func (iterable DeferredGroupJoinIterable[T]) JoinWhere(predicate func(T, any) bool) DeferredGroupJoinIterable[T] {
+
+	return DeferredGroupJoinIterable[T](DeferredJoinIterable[T](iterable).JoinWhere(predicate))
+
+	/*
+		linq.From([]T{...}).
+			GroupJoin(linq.From([]TRight{...}).AsAny()).
+			JoinWhere(
+				func(left T, right any) bool {
+					return ...
+				},
+			)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// BetweenThis sets the group join to match each left item with the right items
+// whose right key is between its lower and upper keys, as with
+// DeferredJoinIterable.BetweenThis.
+func (iterable DeferredGroupJoinIterable[T]) BetweenThis(lowerKeySelector func(T) any, upperKeySelector func(T) any) DeferredGroupJoinIterable[T] {
+
+	return DeferredGroupJoinIterable[T](DeferredJoinIterable[T](iterable).BetweenThis(lowerKeySelector, upperKeySelector))
+
+	/*
+		linq.From([]T{...}).
+			GroupJoin(linq.From([]TRight{...}).AsAny()).
+			BetweenThis(
+				func(left T) any {
+					return left.Start
+				},
+				func(left T) any {
+					return left.End
+				},
+			).
+			Equals("RightKeyField")
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Between sets the group join to match each left item with the right items
+// whose right key is between the values of the given field names, as with
+// DeferredJoinIterable.Between.
+func (iterable DeferredGroupJoinIterable[T]) Between(lowerFieldName string, upperFieldName string) DeferredGroupJoinIterable[T] {
+
+	return DeferredGroupJoinIterable[T](DeferredJoinIterable[T](iterable).Between(lowerFieldName, upperFieldName))
+
+	/*
+		linq.From([]T{...}).
+			GroupJoin(linq.From([]TRight{...}).AsAny()).
+			Between("StartField", "EndField").
+			Equals("RightKeyField")
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Within sets the group join to match each left item with the right items
+// whose right key is no more than distance away from its left key, as with
+// DeferredJoinIterable.Within.
+func (iterable DeferredGroupJoinIterable[T]) Within(distance any) DeferredGroupJoinIterable[T] {
+
+	return DeferredGroupJoinIterable[T](DeferredJoinIterable[T](iterable).Within(distance))
+
+	/*
+		linq.From([]T{...}).
+			GroupJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftTimeField").
+			Equals("RightTimeField").
+			Within(5 * time.Second)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AssumingSorted sets the group join to walk both iterables in lockstep as a
+// merge join, as with DeferredJoinIterable.AssumingSorted.
+func (iterable DeferredGroupJoinIterable[T]) AssumingSorted() DeferredGroupJoinIterable[T] {
+
+	return DeferredGroupJoinIterable[T](DeferredJoinIterable[T](iterable).AssumingSorted())
+
+	/*
+		linq.From([]T{...}).
+			GroupJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftKeyField").
+			Equals("RightKeyField").
+			AssumingSorted()
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// WithKeyNormalizer sets the group join to normalize the left and right keys
+// before comparing them, as with DeferredJoinIterable.WithKeyNormalizer.
+func (iterable DeferredGroupJoinIterable[T]) WithKeyNormalizer(normalizer func(any) any) DeferredGroupJoinIterable[T] {
+
+	return DeferredGroupJoinIterable[T](DeferredJoinIterable[T](iterable).WithKeyNormalizer(normalizer))
+
+	/*
+		linq.From([]T{...}).
+			GroupJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftNameField").
+			Equals("RightNameField").
+			WithKeyNormalizer(func(key any) any { return strings.ToLower(key.(string)) })
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// WithKeyComparer sets the group join to match keys using the given hash and
+// equal functions, as with DeferredJoinIterable.WithKeyComparer.
+func (iterable DeferredGroupJoinIterable[T]) WithKeyComparer(hash func(any) uint64, equal func(any, any) bool) DeferredGroupJoinIterable[T] {
+
+	return DeferredGroupJoinIterable[T](DeferredJoinIterable[T](iterable).WithKeyComparer(hash, equal))
+
+	/*
+		linq.From([]T{...}).
+			GroupJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftTagsField").
+			Equals("RightTagsField").
+			WithKeyComparer(hashTags, equalTags)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AsGroupsThis projects each left item along with a slice of all of its
+// matching right items using the given groupSelector function. Left items
+// with no match are passed an empty slice.
+func (iterable DeferredGroupJoinIterable[T]) AsGroupsThis(groupSelector func(T, []any) any) Iterable[any] {
+
+	return DeferredJoinIterable[T](iterable).AsGroupsThis(groupSelector)
+
+	/*
+		linq.From([]T{...}).
+			GroupJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftKeyField").
+			Equals("RightKeyField").
+			AsGroupsThis(
+				func(left T, rights []any) any {
+					return ...
+				},
+			)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AsGroups projects each left item along with a slice of all of its matching
+// right items as Pair structs.
+func (iterable DeferredGroupJoinIterable[T]) AsGroups() Iterable[any] {
+
+	return DeferredJoinIterable[T](iterable).AsGroups()
+
+	/*
+		linq.From([]T{...}).
+			GroupJoin(linq.From([]TRight{...}).AsAny()).
+			On("LeftKeyField").
+			Equals("RightKeyField").
+			AsGroups()
+	*/
+}
+
+//----------------------------------------------------------------------------//
+// Typed Joining                                                              //
+//----------------------------------------------------------------------------//
+
+////////////////////////////////////////////////////////////////////////////////
+
+// JoinOn returns a new Iterable that inner joins the left and right iterables
+// where the left key equals the right key. Unlike Join, the left and right
+// items keep their own types, so collections of different struct types can
+// be joined without any type assertions.
+func JoinOn[L any, R any, K comparable](
+	left Iterable[L],
+	right Iterable[R],
+	leftKeySelector func(L) K,
+	rightKeySelector func(R) K,
+) Iterable[Pair[L, R]] {
+
+	return selectJoined(
+		hashJoin(left, right, leftKeySelector, rightKeySelector, InnerJoin),
+		func(left Optional[L], right Optional[R]) Pair[L, R] {
+			return Pair[L, R]{Left: left.value, Right: right.value}
+		},
+	)
+
+	/*
+		linq.JoinOn(
+			linq.From([]TLeft{...}),
+			linq.From([]TRight{...}),
+			func(left TLeft) K {
+				return left.KeyField
+			},
+			func(right TRight) K {
+				return right.KeyField
+			},
+		)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// LeftJoinOn returns a new Iterable that left joins the left and right
+// iterables where the left key equals the right key. The right item is an
+// Optional, which is empty for left items with no match.
+func LeftJoinOn[L any, R any, K comparable](
+	left Iterable[L],
+	right Iterable[R],
+	leftKeySelector func(L) K,
+	rightKeySelector func(R) K,
+) Iterable[Pair[L, Optional[R]]] {
+
+	return selectJoined(
+		hashJoin(left, right, leftKeySelector, rightKeySelector, LeftJoin),
+		func(left Optional[L], right Optional[R]) Pair[L, Optional[R]] {
+			return Pair[L, Optional[R]]{Left: left.value, Right: right}
+		},
+	)
+
+	/*
+		linq.LeftJoinOn(
+			linq.From([]TLeft{...}),
+			linq.From([]TRight{...}),
+			func(left TLeft) K { return left.KeyField },
+			func(right TRight) K { return right.KeyField },
+		)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// RightJoinOn returns a new Iterable that right joins the left and right
+// iterables where the left key equals the right key. The left item is an
+// Optional, which is empty for right items with no match.
+func RightJoinOn[L any, R any, K comparable](
+	left Iterable[L],
+	right Iterable[R],
+	leftKeySelector func(L) K,
+	rightKeySelector func(R) K,
+) Iterable[Pair[Optional[L], R]] {
+
+	return selectJoined(
+		hashJoin(left, right, leftKeySelector, rightKeySelector, RightJoin),
+		func(left Optional[L], right Optional[R]) Pair[Optional[L], R] {
+			return Pair[Optional[L], R]{Left: left, Right: right.value}
+		},
+	)
+
+	/*
+		linq.RightJoinOn(
+			linq.From([]TLeft{...}),
+			linq.From([]TRight{...}),
+			func(left TLeft) K { return left.KeyField },
+			func(right TRight) K { return right.KeyField },
+		)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// FullOuterJoinOn returns a new Iterable that full outer joins the left and
+// right iterables where the left key equals the right key. Both items are
+// Optionals, and the side with no match is empty.
+func FullOuterJoinOn[L any, R any, K comparable](
+	left Iterable[L],
+	right Iterable[R],
+	leftKeySelector func(L) K,
+	rightKeySelector func(R) K,
+) Iterable[OptionalPair[L, R]] {
+
+	return selectJoined(
+		hashJoin(left, right, leftKeySelector, rightKeySelector, FullOuterJoin),
+		func(left Optional[L], right Optional[R]) OptionalPair[L, R] {
+			return OptionalPair[L, R]{Left: left, Right: right}
+		},
+	)
+
+	/*
+		linq.FullOuterJoinOn(
+			linq.From([]TLeft{...}),
+			linq.From([]TRight{...}),
+			func(left TLeft) K { return left.KeyField },
+			func(right TRight) K { return right.KeyField },
+		)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// GroupJoinOn returns a new Iterable that yields each left item once along
+// with a slice of all of the right items whose key equals its key. Left items
+// with no match are paired with an empty slice.
+func GroupJoinOn[L any, R any, K comparable](
+	left Iterable[L],
+	right Iterable[R],
+	leftKeySelector func(L) K,
+	rightKeySelector func(R) K,
+) Iterable[Pair[L, []R]] {
+
+	grouped := groupJoin(left, right, hashStrategy(leftKeySelector, rightKeySelector), LeftJoin)
+
+	return Iterable[Pair[L, []R]]{
+		Seq: func(yield func(Pair[L, []R]) bool) {
+			for leftItem, rightItems := range grouped {
+				if !yield(Pair[L, []R]{Left: leftItem, Right: rightItems}) {
+					return
+				}
+			}
+		},
+	}
+
+	/*
+		linq.GroupJoinOn(
+			linq.From([]TLeft{...}),
+			linq.From([]TRight{...}),
+			func(left TLeft) K { return left.KeyField },
+			func(right TRight) K { return right.KeyField },
+		)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// SemiJoinOn returns a new Iterable of the left items that have at least one
+// right item whose key equals their key. Each left item is yielded at most
+// once, no matter how many right items it matches.
+func SemiJoinOn[L any, R any, K comparable](
+	left Iterable[L],
+	right Iterable[R],
+	leftKeySelector func(L) K,
+	rightKeySelector func(R) K,
+) Iterable[L] {
+
+	return selectJoined(
+		hashJoin(left, right, leftKeySelector, rightKeySelector, SemiJoin),
+		func(left Optional[L], _ Optional[R]) L {
+			return left.value
+		},
+	)
+
+	/*
+		linq.SemiJoinOn(
+			linq.From([]TLeft{...}),
+			linq.From([]TRight{...}),
+			func(left TLeft) K { return left.KeyField },
+			func(right TRight) K { return right.KeyField },
+		)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AntiJoinOn returns a new Iterable of the left items that have no right item
+// whose key equals their key.
+func AntiJoinOn[L any, R any, K comparable](
+	left Iterable[L],
+	right Iterable[R],
+	leftKeySelector func(L) K,
+	rightKeySelector func(R) K,
+) Iterable[L] {
+
+	return selectJoined(
+		hashJoin(left, right, leftKeySelector, rightKeySelector, AntiJoin),
+		func(left Optional[L], _ Optional[R]) L {
+			return left.value
+		},
+	)
+
+	/*
+		linq.AntiJoinOn(
+			linq.From([]TLeft{...}),
+			linq.From([]TRight{...}),
+			func(left TLeft) K { return left.KeyField },
+			func(right TRight) K { return right.KeyField },
+		)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// CompositeKeySelectors returns a pair of key selectors for use with the typed
+// join functions that build a CompositeKey from the given left and right field
+// names. The field names are matched up by position, so if L or R is not a
+// struct or pointer to struct, any field name is not found, the number of
+// left and right field names differ, or the fields in the same position have
+// different types, this function will panic.
+func CompositeKeySelectors[L any, R any](leftFieldNames []string, rightFieldNames []string) (func(L) any, func(R) any) {
+
+	if len(leftFieldNames) != len(rightFieldNames) {
+		panic(fmt.Sprintf("join key has %d left fields but %d right fields", len(leftFieldNames), len(rightFieldNames)))
+	}
+
+	leftFieldTypes := getFieldTypes[L](leftFieldNames...)
+	if leftFieldTypes == nil {
+		panic(fmt.Sprintf("item is not a struct or pointer to struct: %v", reflect.TypeFor[L]()))
+	}
+
+	rightFieldTypes := getFieldTypes[R](rightFieldNames...)
+	if rightFieldTypes == nil {
+		panic(fmt.Sprintf("item is not a struct or pointer to struct: %v", reflect.TypeFor[R]()))
+	}
+
+	for i := range leftFieldTypes {
+		if leftFieldTypes[i] != rightFieldTypes[i] {
+			panic(fmt.Sprintf("join key field '%s' is %v, but left key field '%s' is %v",
+				rightFieldNames[i], rightFieldTypes[i], leftFieldNames[i], leftFieldTypes[i]))
+		}
+	}
+
+	return getFieldNamesFunc[L](leftFieldNames...), getFieldNamesFunc[R](rightFieldNames...)
+
+	/*
+		leftKeySelector, rightKeySelector := linq.CompositeKeySelectors[TLeft, TRight](
+			[]string{"LeftKeyField1", "LeftKeyField2"},
+			[]string{"RightKeyField1", "RightKeyField2"},
+		)
+
+		linq.JoinOn(
+			linq.From([]TLeft{...}),
+			linq.From([]TRight{...}),
+			leftKeySelector,
+			rightKeySelector,
+		)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// CrossJoinOf returns a new Iterable that pairs every left item with every
+// right item, in left order and then right order. The right items are read
+// once each time the result is iterated.
+func CrossJoinOf[L any, R any](left Iterable[L], right Iterable[R]) Iterable[Pair[L, R]] {
+
+	return selectJoined(
+		matchJoin(left, right, crossStrategy[L, R](), InnerJoin),
+		func(left Optional[L], right Optional[R]) Pair[L, R] {
+			return Pair[L, R]{Left: left.value, Right: right.value}
+		},
+	)
+
+	/*
+		linq.CrossJoinOf(
+			linq.From([]TLeft{...}),
+			linq.From([]TRight{...}),
+		)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Product returns a new Iterable of every combination of one item from each of
+// the given iterables, in order, with the last iterable varying fastest. The
+// first iterable is streamed and the others are read once each time the
+// result is iterated. Each combination is a new slice. If no iterables are
+// given, or any of them is empty, the result is empty.
+func Product[T any](iterables ...Iterable[T]) Iterable[[]T] {
+
+	return Iterable[[]T]{
+		Seq: func(yield func([]T) bool) {
+			if len(iterables) == 0 {
+				return
+			}
+
+			rest := make([][]T, len(iterables)-1)
+			for i, iterable := range iterables[1:] {
+				rest[i] = slices.Collect(iterable.Seq)
+				if len(rest[i]) == 0 {
+					return
+				}
+			}
+
+			indexes := make([]int, len(rest))
+			for first := range iterables[0].Seq {
+				clear(indexes)
+
+				for {
+					combination := make([]T, 0, len(iterables))
+					combination = append(combination, first)
+					for i, index := range indexes {
+						combination = append(combination, rest[i][index])
+					}
+
+					if !yield(combination) {
+						return
+					}
+
+					// Advance the indexes like an odometer
+					i := len(indexes) - 1
+					for ; i >= 0; i-- {
+						indexes[i]++
+						if indexes[i] < len(rest[i]) {
+							break
+						}
+						indexes[i] = 0
+					}
+					if i < 0 {
+						break
+					}
+				}
+			}
+		},
+	}
+
+	/*
+		linq.Product(
+			linq.From([]T{...}),
+			linq.From([]T{...}),
+			linq.From([]T{...}),
+		)
 	*/
 }
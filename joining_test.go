@@ -1,8 +1,15 @@
 package weaklinq
 
 import (
+	"fmt"
+	"hash/fnv"
 	"iter"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 //----------------------------------------------------------------------------//
@@ -269,6 +276,50 @@ func TestEquals(t *testing.T) {
 
 	//----------------------------------------------------------------------------//
 
+	t.Run("type mismatch", func(t *testing.T) {
+
+		type wideRef struct {
+			Id int64
+		}
+
+		result := From([]testStruct{{Id: 1}}).
+			Join(From([]wideRef{{Id: 1}}).AsAny()).
+			On("Id").
+			Equals("Id")
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		result.AsPairs().AndAssignToSlice(&[]Pair[testStruct, any]{})
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("type mismatch with normalizer", func(t *testing.T) {
+
+		type wideRef struct {
+			Id int64
+		}
+
+		pairs := make([]Pair[testStruct, any], 0)
+		From([]testStruct{{Id: 1}}).
+			Join(From([]wideRef{{Id: 1}}).AsAny()).
+			On("Id").
+			Equals("Id").
+			WithKeyNormalizer(func(key any) any { return fmt.Sprint(key) }).
+			AsPairs().
+			AndAssignToSlice(&pairs)
+
+		if len(pairs) != 1 {
+			t.Errorf("Expected 1 pair but got %v", pairs)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
 	t.Run("bad field name", func(t *testing.T) {
 		testItems := []testStruct{
 			{Id: 1, Name: "Test 1"},
@@ -296,501 +347,2494 @@ func TestEquals(t *testing.T) {
 
 ////////////////////////////////////////////////////////////////////////////////
 
-func TestAsThis(t *testing.T) {
-	testItems := []testStruct{
-		{Id: 1, Name: "Test 1"},
-		{Id: 2, Name: "Test 2"},
-		{Id: 4, Name: "Test 4"},
+func TestCompositeJoinKeys(t *testing.T) {
+
+	type membership struct {
+		TenantId int
+		UserId   int
+		Role     string
 	}
-	testItems2 := []testStruct{
-		{Id: 1, Name: "Test A"},
-		{Id: 2, Name: "Test B"},
-		{Id: 3, Name: "Test C"},
+	type login struct {
+		Tenant int
+		User   int
+		When   string
+	}
+	type badLogin struct {
+		Tenant int
+		User   string
 	}
 
-	result := From(testItems).
-		JoinSlice(testItems2).
-		On("Id").
-		AsThis(func(left testStruct, right any) any {
-			return struct {
-				Name      string
-				RightName string
-			}{
-				Name:      left.Name,
-				RightName: right.(testStruct).Name,
-			}
-		})
+	memberships := []membership{
+		{TenantId: 1, UserId: 1, Role: "Admin"},
+		{TenantId: 1, UserId: 2, Role: "Member"},
+		{TenantId: 2, UserId: 1, Role: "Member"},
+	}
+	logins := []login{
+		{Tenant: 2, User: 1, When: "Monday"},
+		{Tenant: 1, User: 1, When: "Tuesday"},
+		{Tenant: 1, User: 3, When: "Wednesday"},
+	}
 
-	resultIterator, _ := iter.Pull(result.Seq)
+	//----------------------------------------------------------------------------//
 
-	if resultIterator == nil {
-		t.Errorf("Expected iterator but got nil")
-	}
+	t.Run("generic", func(t *testing.T) {
 
-	if item, ok := resultIterator(); ok {
-		combined := item.(struct {
-			Name      string
-			RightName string
-		})
+		result := make([]string, 0)
+		From(memberships).
+			Join(From(logins).AsAny()).
+			On("TenantId", "UserId").
+			Equals("Tenant", "User").
+			AsThis(func(left membership, right any) any {
+				return left.Role + " " + right.(login).When
+			}).
+			AndAssignToSlice(&result)
 
-		if combined.Name != testItems[0].Name {
-			t.Errorf("Expected first left name but got %v", combined.Name)
+		expected := []string{"Admin Tuesday", "Member Monday"}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
 		}
+	})
 
-		if combined.RightName != testItems2[0].Name {
-			t.Errorf("Expected first right name but got %v", combined.RightName)
-		}
-	}
+	//----------------------------------------------------------------------------//
 
-	if item, ok := resultIterator(); ok {
-		combined := item.(struct {
-			Name      string
-			RightName string
-		})
+	t.Run("same field names", func(t *testing.T) {
 
-		if combined.Name != testItems[1].Name {
-			t.Errorf("Expected second left name but got %v", combined.Name)
+		left := []testStruct{
+			{Id: 1, Name: "A"},
+			{Id: 1, Name: "B"},
+		}
+		right := []testStruct{
+			{Id: 1, Name: "B"},
 		}
 
-		if combined.RightName != testItems2[1].Name {
-			t.Errorf("Expected second right name but got %v", combined.RightName)
+		result := make([]testStruct, 0)
+		From(left).
+			SemiJoinSlice(right).
+			On("Id", "Name").
+			AsLeftItems().
+			AndAssignToSlice(&result)
+
+		if !slices.Equal(result, []testStruct{left[1]}) {
+			t.Errorf("Expected %v but got %v", left[1:], result)
 		}
-	}
+	})
 
-	if _, ok := resultIterator(); ok {
-		t.Errorf("Expected no item but got an item")
-	}
+	//----------------------------------------------------------------------------//
+
+	t.Run("arity mismatch", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		From(memberships).
+			Join(From(logins).AsAny()).
+			On("TenantId", "UserId").
+			Equals("Tenant")
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("type mismatch", func(t *testing.T) {
+
+		result := From(memberships).
+			Join(From([]badLogin{{Tenant: 1, User: "1"}}).AsAny()).
+			On("TenantId", "UserId").
+			Equals("Tenant", "User")
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		result.AsPairs().AndAssignToSlice(&[]Pair[membership, any]{})
+	})
+
+	//----------------------------------------------------------------------------//
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 
-func TestAsPairs(t *testing.T) {
-	testItems := []testStruct{
-		{Id: 1, Name: "Test 1"},
-		{Id: 2, Name: "Test 2"},
+type testSession struct {
+	Id    int
+	Start time.Time
+	End   time.Time
+}
+
+type testEvent struct {
+	Name string
+	Ts   time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestJoinWhere(t *testing.T) {
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sessions := []testSession{
+		{Id: 1, Start: base, End: base.Add(10 * time.Minute)},
+		{Id: 2, Start: base.Add(5 * time.Minute), End: base.Add(15 * time.Minute)},
+		{Id: 3, Start: base.Add(time.Hour), End: base.Add(2 * time.Hour)},
 	}
-	testItems2 := []testStruct{
-		{Id: 1, Name: "Test A"},
-		{Id: 2, Name: "Test B"},
+	events := []testEvent{
+		{Name: "B", Ts: base.Add(7 * time.Minute)},
+		{Name: "A", Ts: base.Add(2 * time.Minute)},
+		{Name: "C", Ts: base.Add(10 * time.Minute)},
 	}
 
-	result := make([]Pair[testStruct, any], 0)
-	From(testItems).
-		JoinSlice(testItems2).
-		On("Id").
-		AsPairs().
+	result := make([]string, 0)
+	From(sessions).
+		LeftJoin(From(events).AsAny()).
+		JoinWhere(func(left testSession, right any) bool {
+			ts := right.(testEvent).Ts
+			return !ts.Before(left.Start) && ts.Before(left.End)
+		}).
+		AsOptionalThis(func(left Optional[testSession], right Optional[any]) any {
+			if !right.HasValue() {
+				return fmt.Sprintf("%d/-", left.Value().Id)
+			}
+			return fmt.Sprintf("%d/%s", left.Value().Id, right.Value().(testEvent).Name)
+		}).
 		AndAssignToSlice(&result)
 
-	if result[0].Left != testItems[0] {
-		t.Errorf("Expected first left item but got %v", result[0].Left)
+	expected := []string{"1/B", "1/A", "2/B", "2/C", "3/-"}
+	if !slices.Equal(result, expected) {
+		t.Errorf("Expected %v but got %v", expected, result)
 	}
+}
 
-	if result[0].Right != testItems2[0] {
-		t.Errorf("Expected first right item but got %v", result[0].Right)
-	}
+////////////////////////////////////////////////////////////////////////////////
 
-	if result[1].Left != testItems[1] {
-		t.Errorf("Expected second left item but got %v", result[1].Left)
-	}
+func TestBetween(t *testing.T) {
 
-	if result[1].Right != testItems2[1] {
-		t.Errorf("Expected second right item but got %v", result[1].Right)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sessions := []testSession{
+		{Id: 1, Start: base, End: base.Add(10 * time.Minute)},
+		{Id: 2, Start: base.Add(5 * time.Minute), End: base.Add(15 * time.Minute)},
+		{Id: 3, Start: base.Add(time.Hour), End: base.Add(2 * time.Hour)},
 	}
-
-	if len(result) != 2 {
-		t.Errorf("Expected 2 items but got %v", len(result))
+	events := []testEvent{
+		{Name: "B", Ts: base.Add(7 * time.Minute)},
+		{Name: "A", Ts: base.Add(2 * time.Minute)},
+		{Name: "C", Ts: base.Add(10 * time.Minute)},
+		{Name: "D", Ts: base.Add(3 * time.Hour)},
 	}
-}
 
-////////////////////////////////////////////////////////////////////////////////
+	//----------------------------------------------------------------------------//
 
-func TestLeftJoin(t *testing.T) {
-	t.Run("all items match", func(t *testing.T) {
-		left := []testStruct{
-			{Id: 1, Name: "Left 1"},
-			{Id: 2, Name: "Left 2"},
-		}
-		right := []testStruct{
-			{Id: 1, Name: "Right 1"},
-			{Id: 2, Name: "Right 2"},
+	t.Run("field names", func(t *testing.T) {
+
+		result := make([]string, 0)
+		From(sessions).
+			FullOuterJoin(From(events).AsAny()).
+			Between("Start", "End").
+			Equals("Ts").
+			AsOptionalThis(func(left Optional[testSession], right Optional[any]) any {
+				name := "-"
+				if right.HasValue() {
+					name = right.Value().(testEvent).Name
+				}
+				return fmt.Sprintf("%d/%s", left.OrElse(testSession{}).Id, name)
+			}).
+			AndAssignToSlice(&result)
+
+		// The upper bound is exclusive, so C only falls in session 2, and the
+		// matches keep their right-side order
+		expected := []string{"1/B", "1/A", "2/B", "2/C", "3/-", "0/D"}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
 		}
+	})
 
-		result := make([]Pair[testStruct, any], 0)
-		From(left).
-			LeftJoinSlice(right).
-			On("Id").
-			AsPairs().
+	//----------------------------------------------------------------------------//
+
+	t.Run("selectors", func(t *testing.T) {
+
+		result := make([]int, 0)
+		From([]int{0, 10, 20}).
+			Join(From([]int{5, 25, 12, 10, 9}).AsAny()).
+			BetweenThis(
+				func(left int) any { return left },
+				func(left int) any { return left + 10 },
+			).
+			AsThis(func(left int, right any) any {
+				return right.(int)
+			}).
 			AndAssignToSlice(&result)
 
-		if len(result) != 2 {
-			t.Errorf("Expected 2 items but got %v", len(result))
+		expected := []int{5, 9, 12, 10, 25}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
 		}
+	})
 
-		if result[0].Right == nil {
-			t.Errorf("Expected Right 1 but got nil")
-		}
+	//----------------------------------------------------------------------------//
+
+	t.Run("unordered keys", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		result := make([]any, 0)
+		From(sessions).
+			Join(From([]testStruct{{Id: 1}, {Id: 2}}).AsAny()).
+			Between("Start", "End").
+			AsThis(func(left testSession, right any) any { return right }).
+			AndAssignToSlice(&result)
 	})
 
-	t.Run("left has unmatched items", func(t *testing.T) {
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestWithin(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("numeric", func(t *testing.T) {
+
 		left := []testStruct{
-			{Id: 1, Name: "Left 1"},
-			{Id: 2, Name: "Left 2"},
-			{Id: 4, Name: "Left 4"},
+			{Id: 10, Name: "Left 10"},
+			{Id: 50, Name: "Left 50"},
 		}
 		right := []testStruct{
-			{Id: 1, Name: "Right 1"},
-			{Id: 2, Name: "Right 2"},
-			{Id: 3, Name: "Right 3"},
+			{Id: 13, Name: "Right 13"},
+			{Id: 7, Name: "Right 7"},
+			{Id: 6, Name: "Right 6"},
+			{Id: 30, Name: "Right 30"},
 		}
 
-		result := make([]Pair[testStruct, any], 0)
+		result := make([]string, 0)
 		From(left).
-			LeftJoinSlice(right).
+			JoinSlice(right).
 			On("Id").
-			AsPairs().
+			Within(4).
+			AsThis(func(left testStruct, right any) any {
+				return left.Name + "/" + right.(testStruct).Name
+			}).
 			AndAssignToSlice(&result)
 
-		// All 3 left items should be present
-		if len(result) != 3 {
-			t.Errorf("Expected 3 items but got %v", len(result))
-		}
-
-		// First two should have matches
-		if result[0].Left.Name != "Left 1" || result[0].Right.(testStruct).Name != "Right 1" {
-			t.Errorf("Expected Left 1 and Right 1 but got %v and %v", result[0].Left.Name, result[0].Right)
+		expected := []string{"Left 10/Right 13", "Left 10/Right 7", "Left 10/Right 6"}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
 		}
+	})
 
-		if result[1].Left.Name != "Left 2" || result[1].Right.(testStruct).Name != "Right 2" {
-			t.Errorf("Expected Left 2 and Right 2 but got %v and %v", result[1].Left.Name, result[1].Right)
-		}
+	//----------------------------------------------------------------------------//
 
-		// Third should have nil right
-		if result[2].Left.Name != "Left 4" || result[2].Right != nil {
-			t.Errorf("Expected Left 4 and nil but got %v and %v", result[2].Left.Name, result[2].Right)
-		}
-	})
+	t.Run("time", func(t *testing.T) {
 
-	t.Run("no matches", func(t *testing.T) {
-		left := []testStruct{
-			{Id: 1, Name: "Left 1"},
-			{Id: 2, Name: "Left 2"},
+		base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		left := []testEvent{
+			{Name: "Trade", Ts: base},
 		}
-		right := []testStruct{
-			{Id: 3, Name: "Right 3"},
-			{Id: 4, Name: "Right 4"},
+		right := []testEvent{
+			{Name: "Early", Ts: base.Add(-2 * time.Second)},
+			{Name: "Late", Ts: base.Add(5 * time.Second)},
+			{Name: "Edge", Ts: base.Add(3 * time.Second)},
 		}
 
-		result := make([]Pair[testStruct, any], 0)
+		result := make([]string, 0)
 		From(left).
-			LeftJoinSlice(right).
-			On("Id").
-			AsPairs().
+			Join(From(right).AsAny()).
+			On("Ts").
+			Within(3 * time.Second).
+			AsThis(func(left testEvent, right any) any {
+				return right.(testEvent).Name
+			}).
 			AndAssignToSlice(&result)
 
-		// All left items should be present with nil right
-		if len(result) != 2 {
-			t.Errorf("Expected 2 items but got %v", len(result))
+		expected := []string{"Early", "Edge"}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
 		}
+	})
 
-		if result[0].Left.Name != "Left 1" || result[0].Right != nil {
-			t.Errorf("Expected Left 1 and nil but got %v and %v", result[0].Left.Name, result[0].Right)
+	//----------------------------------------------------------------------------//
+
+	t.Run("type limits", func(t *testing.T) {
+
+		signed := make([]Pair[int8, any], 0)
+		From([]int8{120, math.MaxInt8}).
+			JoinSlice([]int8{120, 125}).
+			Within(10).
+			AsPairs().
+			AndAssignToSlice(&signed)
+
+		if len(signed) != 4 {
+			t.Errorf("Expected 4 pairs but got %v", signed)
 		}
 
-		if result[1].Left.Name != "Left 2" || result[1].Right != nil {
-			t.Errorf("Expected Left 2 and nil but got %v and %v", result[1].Left.Name, result[1].Right)
+		unsigned := make([]Pair[uint8, any], 0)
+		From([]uint8{250, math.MaxUint8}).
+			JoinSlice([]uint8{250}).
+			Within(10).
+			AsPairs().
+			AndAssignToSlice(&unsigned)
+
+		if len(unsigned) != 2 {
+			t.Errorf("Expected 2 pairs but got %v", unsigned)
 		}
 	})
-}
 
-////////////////////////////////////////////////////////////////////////////////
+	//----------------------------------------------------------------------------//
 
-func TestRightJoin(t *testing.T) {
-	t.Run("all items match", func(t *testing.T) {
-		left := []testStruct{
-			{Id: 1, Name: "Left 1"},
-			{Id: 2, Name: "Left 2"},
-		}
-		right := []testStruct{
-			{Id: 1, Name: "Right 1"},
-			{Id: 2, Name: "Right 2"},
-		}
+	t.Run("distance beyond type", func(t *testing.T) {
 
-		result := make([]Pair[testStruct, any], 0)
-		From(left).
-			RightJoinSlice(right).
-			On("Id").
+		pairs := make([]Pair[int8, any], 0)
+		From([]int8{0}).
+			JoinSlice([]int8{100}).
+			Within(300).
 			AsPairs().
-			AndAssignToSlice(&result)
+			AndAssignToSlice(&pairs)
 
-		if len(result) != 2 {
-			t.Errorf("Expected 2 items but got %v", len(result))
+		if len(pairs) != 1 || pairs[0].Right != int8(100) {
+			t.Errorf("Expected [{0 100}] but got %v", pairs)
 		}
+	})
 
-		if result[0].Left.Id == 0 {
-			t.Errorf("Expected Left 1 but got zero value")
-		}
+	//----------------------------------------------------------------------------//
+
+	t.Run("negative distance", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		pairs := make([]Pair[uint8, any], 0)
+		From([]uint8{0}).
+			JoinSlice([]uint8{100}).
+			Within(-1).
+			AsPairs().
+			AndAssignToSlice(&pairs)
 	})
 
-	t.Run("right has unmatched items", func(t *testing.T) {
-		left := []testStruct{
-			{Id: 1, Name: "Left 1"},
-			{Id: 2, Name: "Left 2"},
-			{Id: 4, Name: "Left 4"},
-		}
-		right := []testStruct{
-			{Id: 1, Name: "Right 1"},
-			{Id: 2, Name: "Right 2"},
-			{Id: 3, Name: "Right 3"},
-		}
+	//----------------------------------------------------------------------------//
 
-		result := make([]Pair[testStruct, any], 0)
-		From(left).
-			RightJoinSlice(right).
-			On("Id").
+	t.Run("fractional distance", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		pairs := make([]Pair[int, any], 0)
+		From([]int{10}).
+			JoinSlice([]int{10, 11}).
+			Within(0.5).
 			AsPairs().
-			AndAssignToSlice(&result)
+			AndAssignToSlice(&pairs)
+	})
 
-		// All 3 right items should be present
-		if len(result) != 3 {
-			t.Errorf("Expected 3 items but got %v", len(result))
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestAsOfJoin(t *testing.T) {
+
+	type trade struct {
+		Id     int
+		Symbol string
+		Time   time.Time
+	}
+	type quote struct {
+		Symbol string
+		Time   time.Time
+		Price  float64
+	}
+
+	base := time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC)
+	trades := []trade{
+		{Id: 1, Symbol: "AAA", Time: base.Add(5 * time.Second)},
+		{Id: 2, Symbol: "BBB", Time: base.Add(5 * time.Second)},
+		{Id: 3, Symbol: "AAA", Time: base.Add(20 * time.Second)},
+		{Id: 4, Symbol: "AAA", Time: base},
+		{Id: 5, Symbol: "CCC", Time: base},
+	}
+	quotes := []quote{
+		{Symbol: "AAA", Time: base.Add(10 * time.Second), Price: 3},
+		{Symbol: "AAA", Time: base.Add(2 * time.Second), Price: 1},
+		{Symbol: "BBB", Time: base.Add(1 * time.Second), Price: 10},
+		{Symbol: "AAA", Time: base.Add(5 * time.Second), Price: 2},
+		{Symbol: "AAA", Time: base.Add(5 * time.Second), Price: 2.5},
+	}
+
+	priceOf := func(left Optional[trade], right Optional[any]) any {
+		if !right.HasValue() {
+			return fmt.Sprintf("%d/-", left.Value().Id)
 		}
+		return fmt.Sprintf("%d/%v", left.Value().Id, right.Value().(quote).Price)
+	}
 
-		// Find the matched and unmatched items
-		matchedCount := 0
-		unmatchedCount := 0
-		for _, pair := range result {
-			if pair.Left.Id == 0 {
-				unmatchedCount++
-				// Should be Right 3 (unmatched)
-				if pair.Right.(testStruct).Id != 3 {
-					t.Errorf("Expected unmatched right item to have Id 3 but got %v", pair.Right.(testStruct).Id)
-				}
-			} else {
-				matchedCount++
-			}
+	//----------------------------------------------------------------------------//
+
+	t.Run("preceding", func(t *testing.T) {
+
+		result := make([]string, 0)
+		From(trades).
+			AsOfJoin(From(quotes).AsAny()).
+			On("Time").
+			By("Symbol").
+			AsOptionalThis(priceOf).
+			AndAssignToSlice(&result)
+
+		// Trade 1 matches the later of the two quotes at the same time, and
+		// trades with no earlier quote are kept
+		expected := []string{"1/2.5", "2/10", "3/3", "4/-", "5/-"}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
 		}
+	})
 
-		if matchedCount != 2 {
-			t.Errorf("Expected 2 matched items but got %v", matchedCount)
+	//----------------------------------------------------------------------------//
+
+	t.Run("following", func(t *testing.T) {
+
+		result := make([]string, 0)
+		From(trades).
+			AsOfJoin(From(quotes).AsAny()).
+			On("Time").
+			By("Symbol").
+			Following().
+			AsOptionalThis(priceOf).
+			AndAssignToSlice(&result)
+
+		expected := []string{"1/2", "2/-", "3/-", "4/1", "5/-"}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
 		}
+	})
 
-		if unmatchedCount != 1 {
-			t.Errorf("Expected 1 unmatched item but got %v", unmatchedCount)
+	//----------------------------------------------------------------------------//
+
+	t.Run("tolerance", func(t *testing.T) {
+
+		result := make([]string, 0)
+		From(trades).
+			AsOfJoin(From(quotes).AsAny()).
+			On("Time").
+			By("Symbol").
+			WithTolerance(5 * time.Second).
+			AsOptionalThis(priceOf).
+			AndAssignToSlice(&result)
+
+		// Trade 3 is 10 seconds after its nearest quote
+		expected := []string{"1/2.5", "2/10", "3/-", "4/-", "5/-"}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
 		}
 	})
 
-	t.Run("no matches", func(t *testing.T) {
-		left := []testStruct{
-			{Id: 1, Name: "Left 1"},
-			{Id: 2, Name: "Left 2"},
+	//----------------------------------------------------------------------------//
+
+	t.Run("tolerance at type limits", func(t *testing.T) {
+
+		pairs := make([]Pair[int8, any], 0)
+		From([]int8{120}).
+			AsOfJoinSlice([]int8{125}).
+			Following().
+			WithTolerance(10).
+			AsPairs().
+			AndAssignToSlice(&pairs)
+
+		if len(pairs) != 1 || pairs[0].Right != int8(125) {
+			t.Errorf("Expected [{120 125}] but got %v", pairs)
 		}
-		right := []testStruct{
-			{Id: 3, Name: "Right 3"},
-			{Id: 4, Name: "Right 4"},
+
+		preceding := make([]Pair[int8, any], 0)
+		From([]int8{math.MinInt8 + 2}).
+			AsOfJoinSlice([]int8{math.MinInt8}).
+			WithTolerance(10).
+			AsPairs().
+			AndAssignToSlice(&preceding)
+
+		if len(preceding) != 1 || preceding[0].Right != int8(math.MinInt8) {
+			t.Errorf("Expected [{-126 -128}] but got %v", preceding)
 		}
+	})
 
-		result := make([]Pair[testStruct, any], 0)
-		From(left).
-			RightJoinSlice(right).
-			On("Id").
+	//----------------------------------------------------------------------------//
+
+	t.Run("following on other joins", func(t *testing.T) {
+
+		pairs := make([]Pair[int, any], 0)
+		From([]int{5}).
+			JoinSlice([]int{7}).
+			Following().
 			AsPairs().
-			AndAssignToSlice(&result)
+			AndAssignToSlice(&pairs)
 
-		// All right items should be present with zero-value left
-		if len(result) != 2 {
-			t.Errorf("Expected 2 items but got %v", len(result))
+		if len(pairs) != 0 {
+			t.Errorf("Expected no pairs but got %v", pairs)
 		}
+	})
 
-		if result[0].Left.Id != 0 || result[0].Right.(testStruct).Name != "Right 3" {
-			t.Errorf("Expected zero-value left and Right 3 but got %v and %v", result[0].Left, result[0].Right)
+	//----------------------------------------------------------------------------//
+
+	t.Run("tolerance beyond type", func(t *testing.T) {
+
+		pairs := make([]Pair[int8, any], 0)
+		From([]int8{120}).
+			AsOfJoinSlice([]int8{-100}).
+			WithTolerance(300).
+			AsPairs().
+			AndAssignToSlice(&pairs)
+
+		if len(pairs) != 1 || pairs[0].Right != int8(-100) {
+			t.Errorf("Expected [{120 -100}] but got %v", pairs)
 		}
+	})
 
-		if result[1].Left.Id != 0 || result[1].Right.(testStruct).Name != "Right 4" {
-			t.Errorf("Expected zero-value left and Right 4 but got %v and %v", result[1].Left, result[1].Right)
+	//----------------------------------------------------------------------------//
+
+	t.Run("fractional tolerance", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		pairs := make([]Pair[int, any], 0)
+		From([]int{5}).
+			AsOfJoinSlice([]int{4}).
+			WithTolerance(1.9).
+			AsPairs().
+			AndAssignToSlice(&pairs)
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("without by keys", func(t *testing.T) {
+
+		pairs := make([]Pair[int, any], 0)
+		From([]int{1, 5, 9}).
+			AsOfJoinSlice([]int{4, 0, 8}).
+			AsPairs().
+			AndAssignToSlice(&pairs)
+
+		expected := []Pair[int, any]{{Left: 1, Right: 0}, {Left: 5, Right: 4}, {Left: 9, Right: 8}}
+		if !slices.Equal(pairs, expected) {
+			t.Errorf("Expected %v but got %v", expected, pairs)
 		}
 	})
+
+	//----------------------------------------------------------------------------//
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 
-func TestFullOuterJoin(t *testing.T) {
-	t.Run("all items match", func(t *testing.T) {
-		left := []testStruct{
-			{Id: 1, Name: "Left 1"},
-			{Id: 2, Name: "Left 2"},
-		}
-		right := []testStruct{
-			{Id: 1, Name: "Right 1"},
-			{Id: 2, Name: "Right 2"},
-		}
+func TestAssumingSorted(t *testing.T) {
 
-		result := make([]Pair[testStruct, any], 0)
-		From(left).
-			FullOuterJoinSlice(right).
-			On("Id").
-			AsPairs().
-			AndAssignToSlice(&result)
+	left := []testStruct{
+		{Id: 1, Name: "L1"},
+		{Id: 2, Name: "L2a"},
+		{Id: 2, Name: "L2b"},
+		{Id: 4, Name: "L4"},
+	}
+	right := []testStruct{
+		{Id: 0, Name: "R0"},
+		{Id: 2, Name: "R2a"},
+		{Id: 2, Name: "R2b"},
+		{Id: 3, Name: "R3"},
+		{Id: 4, Name: "R4"},
+	}
 
-		if len(result) != 2 {
-			t.Errorf("Expected 2 items but got %v", len(result))
-		}
+	namesOf := func(left Optional[testStruct], right Optional[any]) any {
+		return left.OrElse(testStruct{Name: "-"}).Name + "/" + right.OrElse(testStruct{Name: "-"}).(testStruct).Name
+	}
 
-		// All should be matched pairs
-		for i, pair := range result {
-			if pair.Left.Id == 0 || pair.Right == nil {
-				t.Errorf("Expected matched pair at index %v but got %v and %v", i, pair.Left, pair.Right)
+	//----------------------------------------------------------------------------//
+
+	t.Run("join types", func(t *testing.T) {
+
+		cases := []struct {
+			joinType joinType
+			expected []string
+		}{
+			{
+				joinType: InnerJoin,
+				expected: []string{"L2a/R2a", "L2a/R2b", "L2b/R2a", "L2b/R2b", "L4/R4"},
+			},
+			{
+				joinType: LeftJoin,
+				expected: []string{"L1/-", "L2a/R2a", "L2a/R2b", "L2b/R2a", "L2b/R2b", "L4/R4"},
+			},
+			{
+				joinType: RightJoin,
+				expected: []string{"-/R0", "L2a/R2a", "L2a/R2b", "L2b/R2a", "L2b/R2b", "-/R3", "L4/R4"},
+			},
+			{
+				joinType: FullOuterJoin,
+				expected: []string{"-/R0", "L1/-", "L2a/R2a", "L2a/R2b", "L2b/R2a", "L2b/R2b", "-/R3", "L4/R4"},
+			},
+			{
+				joinType: SemiJoin,
+				expected: []string{"L2a/-", "L2b/-", "L4/-"},
+			},
+			{
+				joinType: AntiJoin,
+				expected: []string{"L1/-"},
+			},
+		}
+
+		for _, c := range cases {
+			joinIterable := defaultJoinIterable(From(left), From(right).AsAny())
+			joinIterable.joinType = c.joinType
+
+			result := make([]string, 0)
+			DeferredJoinIterable[testStruct](joinIterable).
+				On("Id").
+				AssumingSorted().
+				AsOptionalThis(namesOf).
+				AndAssignToSlice(&result)
+
+			if !slices.Equal(result, c.expected) {
+				t.Errorf("Expected %v for join type %v but got %v", c.expected, c.joinType, result)
 			}
 		}
 	})
 
-	t.Run("both sides have unmatched items", func(t *testing.T) {
-		left := []testStruct{
-			{Id: 1, Name: "Left 1"},
-			{Id: 2, Name: "Left 2"},
-			{Id: 4, Name: "Left 4"},
-		}
-		right := []testStruct{
-			{Id: 1, Name: "Right 1"},
-			{Id: 2, Name: "Right 2"},
-			{Id: 3, Name: "Right 3"},
-		}
+	//----------------------------------------------------------------------------//
 
-		result := make([]Pair[testStruct, any], 0)
+	t.Run("groups", func(t *testing.T) {
+
+		result := make([]int, 0)
 		From(left).
-			FullOuterJoinSlice(right).
+			GroupJoinSlice(right).
 			On("Id").
-			AsPairs().
+			AssumingSorted().
+			AsGroupsThis(func(left testStruct, rights []any) any {
+				return len(rights)
+			}).
 			AndAssignToSlice(&result)
 
-		// Should have 2 matched + 1 left-only + 1 right-only = 4 items
-		if len(result) != 4 {
-			t.Errorf("Expected 4 items but got %v", len(result))
+		expected := []int{0, 2, 2, 1}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
 		}
+	})
 
-		matchedCount := 0
-		leftOnlyCount := 0
-		rightOnlyCount := 0
+	//----------------------------------------------------------------------------//
 
-		for _, pair := range result {
-			if pair.Left.Id != 0 && pair.Right != nil {
-				matchedCount++
-			} else if pair.Left.Id != 0 && pair.Right == nil {
-				leftOnlyCount++
-				if pair.Left.Id != 4 {
-					t.Errorf("Expected left-only item to have Id 4 but got %v", pair.Left.Id)
-				}
-			} else if pair.Left.Id == 0 && pair.Right != nil {
-				rightOnlyCount++
-				if pair.Right.(testStruct).Id != 3 {
-					t.Errorf("Expected right-only item to have Id 3 but got %v", pair.Right.(testStruct).Id)
+	t.Run("streams both sides", func(t *testing.T) {
+
+		reads := 0
+		counting := func(yield func(int) bool) {
+			for i := range 1000 {
+				reads++
+				if !yield(i) {
+					return
 				}
 			}
 		}
 
-		if matchedCount != 2 {
-			t.Errorf("Expected 2 matched items but got %v", matchedCount)
-		}
-
-		if leftOnlyCount != 1 {
-			t.Errorf("Expected 1 left-only item but got %v", leftOnlyCount)
+		for pair := range From([]int{0, 1, 2}).
+			Join(Iterable[int]{Seq: counting}.AsAny()).
+			AssumingSorted().
+			AsPairs().Seq {
+			if pair.(Pair[int, any]).Left == 1 {
+				break
+			}
 		}
 
-		if rightOnlyCount != 1 {
-			t.Errorf("Expected 1 right-only item but got %v", rightOnlyCount)
+		if reads > 3 {
+			t.Errorf("Expected at most 3 right reads but got %v", reads)
 		}
 	})
 
-	t.Run("no matches", func(t *testing.T) {
-		left := []testStruct{
-			{Id: 1, Name: "Left 1"},
-			{Id: 2, Name: "Left 2"},
-		}
-		right := []testStruct{
-			{Id: 3, Name: "Right 3"},
-			{Id: 4, Name: "Right 4"},
-		}
+	//----------------------------------------------------------------------------//
 
-		result := make([]Pair[testStruct, any], 0)
-		From(left).
-			FullOuterJoinSlice(right).
-			On("Id").
-			AsPairs().
-			AndAssignToSlice(&result)
+	t.Run("unsorted input", func(t *testing.T) {
 
-		// All items from both sides should be present
-		if len(result) != 4 {
-			t.Errorf("Expected 4 items but got %v", len(result))
-		}
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
 
-		leftOnlyCount := 0
-		rightOnlyCount := 0
+		result := make([]any, 0)
+		From([]int{1, 3, 2}).
+			JoinSlice([]int{1, 2, 3}).
+			AssumingSorted().
+			AsPairs().
+			AndAssignToSlice(&result)
+	})
 
-		for _, pair := range result {
-			if pair.Left.Id != 0 && pair.Right == nil {
-				leftOnlyCount++
-			} else if pair.Left.Id == 0 && pair.Right != nil {
-				rightOnlyCount++
-			}
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestWithKeyNormalizer(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("case insensitive", func(t *testing.T) {
+
+		result := make([]string, 0)
+		From([]testStruct{{Id: 1, Name: "Alice"}, {Id: 2, Name: "BOB"}}).
+			Join(From([]testStruct{{Id: 3, Name: "alice"}, {Id: 4, Name: "Carol"}}).AsAny()).
+			On("Name").
+			Equals("Name").
+			WithKeyNormalizer(func(key any) any { return strings.ToLower(key.(string)) }).
+			AsThis(func(left testStruct, right any) any {
+				return fmt.Sprintf("%d/%d", left.Id, right.(testStruct).Id)
+			}).
+			AndAssignToSlice(&result)
+
+		expected := []string{"1/3"}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
 		}
+	})
 
-		if leftOnlyCount != 2 {
-			t.Errorf("Expected 2 left-only items but got %v", leftOnlyCount)
+	//----------------------------------------------------------------------------//
+
+	t.Run("float rounding", func(t *testing.T) {
+
+		result := make([]string, 0)
+		From([]float64{1.001, 2.5}).
+			JoinSlice([]float64{0.999, 2.6}).
+			WithKeyNormalizer(func(key any) any { return math.Round(key.(float64)*100) / 100 }).
+			AsThis(func(left float64, right any) any {
+				return fmt.Sprintf("%v/%v", left, right)
+			}).
+			AndAssignToSlice(&result)
+
+		expected := []string{"1.001/0.999"}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
 		}
+	})
 
-		if rightOnlyCount != 2 {
-			t.Errorf("Expected 2 right-only items but got %v", rightOnlyCount)
+	//----------------------------------------------------------------------------//
+
+	t.Run("assuming sorted", func(t *testing.T) {
+
+		result := make([]string, 0)
+		From([]string{"a", "B", "c"}).
+			LeftJoinSlice([]string{"A", "b"}).
+			WithKeyNormalizer(func(key any) any { return strings.ToLower(key.(string)) }).
+			AssumingSorted().
+			AsOptionalThis(func(left Optional[string], right Optional[any]) any {
+				return left.OrElse("-") + "/" + right.OrElse("-").(string)
+			}).
+			AndAssignToSlice(&result)
+
+		expected := []string{"a/A", "B/b", "c/-"}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
 		}
 	})
 
-	t.Run("empty left side", func(t *testing.T) {
-		left := []testStruct{}
-		right := []testStruct{
-			{Id: 1, Name: "Right 1"},
-			{Id: 2, Name: "Right 2"},
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestWithKeyComparer(t *testing.T) {
+
+	type tagged struct {
+		Name string
+		Tags []string
+	}
+
+	hashTags := func(key any) uint64 {
+		hash := fnv.New64a()
+		for _, tag := range key.([]string) {
+			hash.Write([]byte(tag))
+			hash.Write([]byte{0})
 		}
+		return hash.Sum64()
+	}
+	equalTags := func(a any, b any) bool {
+		return slices.Equal(a.([]string), b.([]string))
+	}
 
-		result := make([]Pair[testStruct, any], 0)
+	left := []tagged{
+		{Name: "L1", Tags: []string{"a", "b"}},
+		{Name: "L2", Tags: []string{"c"}},
+		{Name: "L3", Tags: nil},
+	}
+	right := []tagged{
+		{Name: "R1", Tags: []string{"c"}},
+		{Name: "R2", Tags: []string{"a", "b"}},
+		{Name: "R3", Tags: []string{"ab"}},
+		{Name: "R4", Tags: []string{"a", "b"}},
+	}
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("slice keys", func(t *testing.T) {
+
+		result := make([]string, 0)
 		From(left).
-			FullOuterJoinSlice(right).
-			On("Id").
-			AsPairs().
+			LeftJoin(From(right).AsAny()).
+			On("Tags").
+			Equals("Tags").
+			WithKeyComparer(hashTags, equalTags).
+			AsOptionalThis(func(left Optional[tagged], right Optional[any]) any {
+				return left.Value().Name + "/" + right.OrElse(tagged{Name: "-"}).(tagged).Name
+			}).
 			AndAssignToSlice(&result)
 
-		// Should have all right items with zero-value left
-		if len(result) != 2 {
-			t.Errorf("Expected 2 items but got %v", len(result))
+		expected := []string{"L1/R2", "L1/R4", "L2/R1", "L3/-"}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
 		}
+	})
 
-		for _, pair := range result {
-			if pair.Left.Id != 0 {
-				t.Errorf("Expected zero-value left but got %v", pair.Left)
-			}
+	//----------------------------------------------------------------------------//
+
+	t.Run("hash collisions", func(t *testing.T) {
+
+		result := make([]string, 0)
+		From([]string{"a", "B"}).
+			JoinSlice([]string{"A", "b", "c"}).
+			WithKeyComparer(
+				func(any) uint64 { return 0 },
+				func(a any, b any) bool { return strings.EqualFold(a.(string), b.(string)) },
+			).
+			AsThis(func(left string, right any) any {
+				return left + "/" + right.(string)
+			}).
+			AndAssignToSlice(&result)
+
+		expected := []string{"a/A", "B/b"}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
 		}
 	})
 
-	t.Run("empty right side", func(t *testing.T) {
-		left := []testStruct{
-			{Id: 1, Name: "Left 1"},
-			{Id: 2, Name: "Left 2"},
-		}
-		right := []testStruct{}
+	//----------------------------------------------------------------------------//
 
-		result := make([]Pair[testStruct, any], 0)
+	t.Run("group join", func(t *testing.T) {
+
+		counts := make([]int, 0)
 		From(left).
-			FullOuterJoinSlice(right).
-			On("Id").
+			GroupJoin(From(right).AsAny()).
+			On("Tags").
+			Equals("Tags").
+			WithKeyComparer(hashTags, equalTags).
+			AsGroupsThis(func(left tagged, rights []any) any {
+				return len(rights)
+			}).
+			AndAssignToSlice(&counts)
+
+		expected := []int{2, 1, 0}
+		if !slices.Equal(counts, expected) {
+			t.Errorf("Expected %v but got %v", expected, counts)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("missing function", func(t *testing.T) {
+
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("Expected panic but got none")
+			}
+		}()
+
+		From(left).Join(From(right).AsAny()).WithKeyComparer(hashTags, nil)
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestCrossJoin(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("generic", func(t *testing.T) {
+
+		result := make([]string, 0)
+		From([]testStruct{{Name: "A"}, {Name: "B"}}).
+			CrossJoin(From([]int{1, 2, 3}).AsAny()).
+			AsThis(func(left testStruct, right any) any {
+				return fmt.Sprintf("%s%d", left.Name, right.(int))
+			}).
+			AndAssignToSlice(&result)
+
+		expected := []string{"A1", "A2", "A3", "B1", "B2", "B3"}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("pairs", func(t *testing.T) {
+
+		result := make([]Pair[int, any], 0)
+		From([]int{1, 2}).
+			CrossJoinSlice([]int{1, 2}).
 			AsPairs().
 			AndAssignToSlice(&result)
 
-		// Should have all left items with nil right
-		if len(result) != 2 {
-			t.Errorf("Expected 2 items but got %v", len(result))
+		if len(result) != 4 {
+			t.Errorf("Expected 4 items but got %v", len(result))
 		}
+	})
 
-		for _, pair := range result {
-			if pair.Right != nil {
-				t.Errorf("Expected nil right but got %v", pair.Right)
+	//----------------------------------------------------------------------------//
+
+	t.Run("early termination", func(t *testing.T) {
+
+		leftReads := 0
+		left := Iterable[int]{
+			Seq: func(yield func(int) bool) {
+				for i := range 1000 {
+					leftReads++
+					if !yield(i) {
+						return
+					}
+				}
+			},
+		}
+
+		count := 0
+		for range left.CrossJoinSlice([]int{1, 2, 3}).AsPairs().Seq {
+			count++
+			if count == 4 {
+				break
 			}
 		}
+
+		if leftReads != 2 {
+			t.Errorf("Expected 2 left reads but got %v", leftReads)
+		}
 	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestAsThis(t *testing.T) {
+	testItems := []testStruct{
+		{Id: 1, Name: "Test 1"},
+		{Id: 2, Name: "Test 2"},
+		{Id: 4, Name: "Test 4"},
+	}
+	testItems2 := []testStruct{
+		{Id: 1, Name: "Test A"},
+		{Id: 2, Name: "Test B"},
+		{Id: 3, Name: "Test C"},
+	}
+
+	result := From(testItems).
+		JoinSlice(testItems2).
+		On("Id").
+		AsThis(func(left testStruct, right any) any {
+			return struct {
+				Name      string
+				RightName string
+			}{
+				Name:      left.Name,
+				RightName: right.(testStruct).Name,
+			}
+		})
+
+	resultIterator, _ := iter.Pull(result.Seq)
+
+	if resultIterator == nil {
+		t.Errorf("Expected iterator but got nil")
+	}
+
+	if item, ok := resultIterator(); ok {
+		combined := item.(struct {
+			Name      string
+			RightName string
+		})
+
+		if combined.Name != testItems[0].Name {
+			t.Errorf("Expected first left name but got %v", combined.Name)
+		}
+
+		if combined.RightName != testItems2[0].Name {
+			t.Errorf("Expected first right name but got %v", combined.RightName)
+		}
+	}
+
+	if item, ok := resultIterator(); ok {
+		combined := item.(struct {
+			Name      string
+			RightName string
+		})
+
+		if combined.Name != testItems[1].Name {
+			t.Errorf("Expected second left name but got %v", combined.Name)
+		}
+
+		if combined.RightName != testItems2[1].Name {
+			t.Errorf("Expected second right name but got %v", combined.RightName)
+		}
+	}
+
+	if _, ok := resultIterator(); ok {
+		t.Errorf("Expected no item but got an item")
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestAsPairs(t *testing.T) {
+	testItems := []testStruct{
+		{Id: 1, Name: "Test 1"},
+		{Id: 2, Name: "Test 2"},
+	}
+	testItems2 := []testStruct{
+		{Id: 1, Name: "Test A"},
+		{Id: 2, Name: "Test B"},
+	}
+
+	result := make([]Pair[testStruct, any], 0)
+	From(testItems).
+		JoinSlice(testItems2).
+		On("Id").
+		AsPairs().
+		AndAssignToSlice(&result)
+
+	if result[0].Left != testItems[0] {
+		t.Errorf("Expected first left item but got %v", result[0].Left)
+	}
+
+	if result[0].Right != testItems2[0] {
+		t.Errorf("Expected first right item but got %v", result[0].Right)
+	}
+
+	if result[1].Left != testItems[1] {
+		t.Errorf("Expected second left item but got %v", result[1].Left)
+	}
+
+	if result[1].Right != testItems2[1] {
+		t.Errorf("Expected second right item but got %v", result[1].Right)
+	}
+
+	if len(result) != 2 {
+		t.Errorf("Expected 2 items but got %v", len(result))
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestAsOptionalThis(t *testing.T) {
+
+	left := []testStruct{
+		{Id: 0, Name: "Left 0"},
+		{Id: 1, Name: "Left 1"},
+	}
+	right := []testStruct{
+		{Id: 0},
+	}
+
+	result := make([]string, 0)
+	From(left).
+		LeftJoinSlice(right).
+		On("Id").
+		AsOptionalThis(func(left Optional[testStruct], right Optional[any]) any {
+			if !right.HasValue() {
+				return left.Value().Name + " unmatched"
+			}
+			return left.Value().Name + " matched"
+		}).
+		AndAssignToSlice(&result)
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 items but got %v", len(result))
+	}
+
+	if result[0] != "Left 0 matched" {
+		t.Errorf("Expected Left 0 matched but got %v", result[0])
+	}
+
+	if result[1] != "Left 1 unmatched" {
+		t.Errorf("Expected Left 1 unmatched but got %v", result[1])
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestAsOptionalPairs(t *testing.T) {
+
+	left := []testStruct{
+		{Id: 1, Name: "Left 1"},
+		{Id: 2, Name: "Left 2"},
+	}
+	right := []testStruct{
+		{Id: 1, Name: "Right 1"},
+		{Id: 3},
+	}
+
+	result := make([]OptionalPair[testStruct, any], 0)
+	From(left).
+		FullOuterJoinSlice(right).
+		On("Id").
+		AsOptionalPairs().
+		AndAssignToSlice(&result)
+
+	if len(result) != 3 {
+		t.Fatalf("Expected 3 items but got %v", len(result))
+	}
+
+	if result[0].Left.Value().Name != "Left 1" || result[0].Right.Value().(testStruct).Name != "Right 1" {
+		t.Errorf("Expected Left 1 and Right 1 but got %v and %v", result[0].Left, result[0].Right)
+	}
+
+	if result[1].Left.Value().Name != "Left 2" || result[1].Right.HasValue() {
+		t.Errorf("Expected Left 2 and no right but got %v and %v", result[1].Left, result[1].Right)
+	}
+
+	// The unmatched right item has zero-valued fields but is still present
+	if result[2].Left.HasValue() || result[2].Right.Value().(testStruct).Id != 3 {
+		t.Errorf("Expected no left and Right 3 but got %v and %v", result[2].Left, result[2].Right)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestAsGroups(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("group join", func(t *testing.T) {
+
+		left := []testStruct{
+			{Id: 1, Name: "Left 1"},
+			{Id: 2, Name: "Left 2"},
+		}
+		right := []testStruct{
+			{Id: 1, Name: "Right 1a"},
+			{Id: 3, Name: "Right 3"},
+			{Id: 1, Name: "Right 1b"},
+		}
+
+		result := make([]Pair[testStruct, []any], 0)
+		From(left).
+			GroupJoinSlice(right).
+			On("Id").
+			AsGroups().
+			AndAssignToSlice(&result)
+
+		if len(result) != 2 {
+			t.Fatalf("Expected 2 items but got %v", len(result))
+		}
+
+		if result[0].Left.Name != "Left 1" || len(result[0].Right) != 2 {
+			t.Fatalf("Expected Left 1 with 2 matches but got %v", result[0])
+		}
+
+		if result[0].Right[0].(testStruct).Name != "Right 1a" || result[0].Right[1].(testStruct).Name != "Right 1b" {
+			t.Errorf("Expected Right 1a and Right 1b but got %v", result[0].Right)
+		}
+
+		if result[1].Left.Name != "Left 2" || len(result[1].Right) != 0 {
+			t.Errorf("Expected Left 2 with no matches but got %v", result[1])
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("inner join skips unmatched", func(t *testing.T) {
+
+		left := []testStruct{
+			{Id: 1, Name: "Left 1"},
+			{Id: 2, Name: "Left 2"},
+		}
+		right := []testStruct{
+			{Id: 1, Name: "Right 1"},
+		}
+
+		result := make([]int, 0)
+		From(left).
+			JoinSlice(right).
+			On("Id").
+			AsGroupsThis(func(left testStruct, rights []any) any {
+				return len(rights)
+			}).
+			AndAssignToSlice(&result)
+
+		if !slices.Equal(result, []int{1}) {
+			t.Errorf("Expected [1] but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("group join builders", func(t *testing.T) {
+
+		left := []testStruct{
+			{Id: 1, Name: "Left A"},
+			{Id: 2, Name: "Left B"},
+		}
+		right := []testStruct{
+			{Id: 5, Name: "left a"},
+			{Id: 6, Name: "LEFT A"},
+		}
+
+		result := make([]int, 0)
+		From(left).
+			GroupJoinSlice(right).
+			On("Name").
+			WithKeyNormalizer(func(key any) any { return strings.ToLower(key.(string)) }).
+			AsGroupsThis(func(left testStruct, rights []any) any {
+				return len(rights)
+			}).
+			AndAssignToSlice(&result)
+
+		if !slices.Equal(result, []int{2, 0}) {
+			t.Errorf("Expected [2 0] but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("group join only groups", func(t *testing.T) {
+
+		groupJoinType := reflect.TypeFor[DeferredGroupJoinIterable[testStruct]]()
+		for _, name := range []string{"AsThis", "AsPairs", "AsOptionalPairs", "AsLeftItems"} {
+			if _, exists := groupJoinType.MethodByName(name); exists {
+				t.Errorf("Expected no %s on a group join", name)
+			}
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestLeftJoin(t *testing.T) {
+	t.Run("all items match", func(t *testing.T) {
+		left := []testStruct{
+			{Id: 1, Name: "Left 1"},
+			{Id: 2, Name: "Left 2"},
+		}
+		right := []testStruct{
+			{Id: 1, Name: "Right 1"},
+			{Id: 2, Name: "Right 2"},
+		}
+
+		result := make([]Pair[testStruct, any], 0)
+		From(left).
+			LeftJoinSlice(right).
+			On("Id").
+			AsPairs().
+			AndAssignToSlice(&result)
+
+		if len(result) != 2 {
+			t.Errorf("Expected 2 items but got %v", len(result))
+		}
+
+		if result[0].Right == nil {
+			t.Errorf("Expected Right 1 but got nil")
+		}
+	})
+
+	t.Run("left has unmatched items", func(t *testing.T) {
+		left := []testStruct{
+			{Id: 1, Name: "Left 1"},
+			{Id: 2, Name: "Left 2"},
+			{Id: 4, Name: "Left 4"},
+		}
+		right := []testStruct{
+			{Id: 1, Name: "Right 1"},
+			{Id: 2, Name: "Right 2"},
+			{Id: 3, Name: "Right 3"},
+		}
+
+		result := make([]Pair[testStruct, any], 0)
+		From(left).
+			LeftJoinSlice(right).
+			On("Id").
+			AsPairs().
+			AndAssignToSlice(&result)
+
+		// All 3 left items should be present
+		if len(result) != 3 {
+			t.Errorf("Expected 3 items but got %v", len(result))
+		}
+
+		// First two should have matches
+		if result[0].Left.Name != "Left 1" || result[0].Right.(testStruct).Name != "Right 1" {
+			t.Errorf("Expected Left 1 and Right 1 but got %v and %v", result[0].Left.Name, result[0].Right)
+		}
+
+		if result[1].Left.Name != "Left 2" || result[1].Right.(testStruct).Name != "Right 2" {
+			t.Errorf("Expected Left 2 and Right 2 but got %v and %v", result[1].Left.Name, result[1].Right)
+		}
+
+		// Third should have nil right
+		if result[2].Left.Name != "Left 4" || result[2].Right != nil {
+			t.Errorf("Expected Left 4 and nil but got %v and %v", result[2].Left.Name, result[2].Right)
+		}
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		left := []testStruct{
+			{Id: 1, Name: "Left 1"},
+			{Id: 2, Name: "Left 2"},
+		}
+		right := []testStruct{
+			{Id: 3, Name: "Right 3"},
+			{Id: 4, Name: "Right 4"},
+		}
+
+		result := make([]Pair[testStruct, any], 0)
+		From(left).
+			LeftJoinSlice(right).
+			On("Id").
+			AsPairs().
+			AndAssignToSlice(&result)
+
+		// All left items should be present with nil right
+		if len(result) != 2 {
+			t.Errorf("Expected 2 items but got %v", len(result))
+		}
+
+		if result[0].Left.Name != "Left 1" || result[0].Right != nil {
+			t.Errorf("Expected Left 1 and nil but got %v and %v", result[0].Left.Name, result[0].Right)
+		}
+
+		if result[1].Left.Name != "Left 2" || result[1].Right != nil {
+			t.Errorf("Expected Left 2 and nil but got %v and %v", result[1].Left.Name, result[1].Right)
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestRightJoin(t *testing.T) {
+	t.Run("all items match", func(t *testing.T) {
+		left := []testStruct{
+			{Id: 1, Name: "Left 1"},
+			{Id: 2, Name: "Left 2"},
+		}
+		right := []testStruct{
+			{Id: 1, Name: "Right 1"},
+			{Id: 2, Name: "Right 2"},
+		}
+
+		result := make([]Pair[testStruct, any], 0)
+		From(left).
+			RightJoinSlice(right).
+			On("Id").
+			AsPairs().
+			AndAssignToSlice(&result)
+
+		if len(result) != 2 {
+			t.Errorf("Expected 2 items but got %v", len(result))
+		}
+
+		if result[0].Left.Id == 0 {
+			t.Errorf("Expected Left 1 but got zero value")
+		}
+	})
+
+	t.Run("right has unmatched items", func(t *testing.T) {
+		left := []testStruct{
+			{Id: 1, Name: "Left 1"},
+			{Id: 2, Name: "Left 2"},
+			{Id: 4, Name: "Left 4"},
+		}
+		right := []testStruct{
+			{Id: 1, Name: "Right 1"},
+			{Id: 2, Name: "Right 2"},
+			{Id: 3, Name: "Right 3"},
+		}
+
+		result := make([]Pair[testStruct, any], 0)
+		From(left).
+			RightJoinSlice(right).
+			On("Id").
+			AsPairs().
+			AndAssignToSlice(&result)
+
+		// All 3 right items should be present
+		if len(result) != 3 {
+			t.Errorf("Expected 3 items but got %v", len(result))
+		}
+
+		// Find the matched and unmatched items
+		matchedCount := 0
+		unmatchedCount := 0
+		for _, pair := range result {
+			if pair.Left.Id == 0 {
+				unmatchedCount++
+				// Should be Right 3 (unmatched)
+				if pair.Right.(testStruct).Id != 3 {
+					t.Errorf("Expected unmatched right item to have Id 3 but got %v", pair.Right.(testStruct).Id)
+				}
+			} else {
+				matchedCount++
+			}
+		}
+
+		if matchedCount != 2 {
+			t.Errorf("Expected 2 matched items but got %v", matchedCount)
+		}
+
+		if unmatchedCount != 1 {
+			t.Errorf("Expected 1 unmatched item but got %v", unmatchedCount)
+		}
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		left := []testStruct{
+			{Id: 1, Name: "Left 1"},
+			{Id: 2, Name: "Left 2"},
+		}
+		right := []testStruct{
+			{Id: 3, Name: "Right 3"},
+			{Id: 4, Name: "Right 4"},
+		}
+
+		result := make([]Pair[testStruct, any], 0)
+		From(left).
+			RightJoinSlice(right).
+			On("Id").
+			AsPairs().
+			AndAssignToSlice(&result)
+
+		// All right items should be present with zero-value left
+		if len(result) != 2 {
+			t.Errorf("Expected 2 items but got %v", len(result))
+		}
+
+		if result[0].Left.Id != 0 || result[0].Right.(testStruct).Name != "Right 3" {
+			t.Errorf("Expected zero-value left and Right 3 but got %v and %v", result[0].Left, result[0].Right)
+		}
+
+		if result[1].Left.Id != 0 || result[1].Right.(testStruct).Name != "Right 4" {
+			t.Errorf("Expected zero-value left and Right 4 but got %v and %v", result[1].Left, result[1].Right)
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestFullOuterJoin(t *testing.T) {
+	t.Run("all items match", func(t *testing.T) {
+		left := []testStruct{
+			{Id: 1, Name: "Left 1"},
+			{Id: 2, Name: "Left 2"},
+		}
+		right := []testStruct{
+			{Id: 1, Name: "Right 1"},
+			{Id: 2, Name: "Right 2"},
+		}
+
+		result := make([]Pair[testStruct, any], 0)
+		From(left).
+			FullOuterJoinSlice(right).
+			On("Id").
+			AsPairs().
+			AndAssignToSlice(&result)
+
+		if len(result) != 2 {
+			t.Errorf("Expected 2 items but got %v", len(result))
+		}
+
+		// All should be matched pairs
+		for i, pair := range result {
+			if pair.Left.Id == 0 || pair.Right == nil {
+				t.Errorf("Expected matched pair at index %v but got %v and %v", i, pair.Left, pair.Right)
+			}
+		}
+	})
+
+	t.Run("both sides have unmatched items", func(t *testing.T) {
+		left := []testStruct{
+			{Id: 1, Name: "Left 1"},
+			{Id: 2, Name: "Left 2"},
+			{Id: 4, Name: "Left 4"},
+		}
+		right := []testStruct{
+			{Id: 1, Name: "Right 1"},
+			{Id: 2, Name: "Right 2"},
+			{Id: 3, Name: "Right 3"},
+		}
+
+		result := make([]Pair[testStruct, any], 0)
+		From(left).
+			FullOuterJoinSlice(right).
+			On("Id").
+			AsPairs().
+			AndAssignToSlice(&result)
+
+		// Should have 2 matched + 1 left-only + 1 right-only = 4 items
+		if len(result) != 4 {
+			t.Errorf("Expected 4 items but got %v", len(result))
+		}
+
+		matchedCount := 0
+		leftOnlyCount := 0
+		rightOnlyCount := 0
+
+		for _, pair := range result {
+			if pair.Left.Id != 0 && pair.Right != nil {
+				matchedCount++
+			} else if pair.Left.Id != 0 && pair.Right == nil {
+				leftOnlyCount++
+				if pair.Left.Id != 4 {
+					t.Errorf("Expected left-only item to have Id 4 but got %v", pair.Left.Id)
+				}
+			} else if pair.Left.Id == 0 && pair.Right != nil {
+				rightOnlyCount++
+				if pair.Right.(testStruct).Id != 3 {
+					t.Errorf("Expected right-only item to have Id 3 but got %v", pair.Right.(testStruct).Id)
+				}
+			}
+		}
+
+		if matchedCount != 2 {
+			t.Errorf("Expected 2 matched items but got %v", matchedCount)
+		}
+
+		if leftOnlyCount != 1 {
+			t.Errorf("Expected 1 left-only item but got %v", leftOnlyCount)
+		}
+
+		if rightOnlyCount != 1 {
+			t.Errorf("Expected 1 right-only item but got %v", rightOnlyCount)
+		}
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		left := []testStruct{
+			{Id: 1, Name: "Left 1"},
+			{Id: 2, Name: "Left 2"},
+		}
+		right := []testStruct{
+			{Id: 3, Name: "Right 3"},
+			{Id: 4, Name: "Right 4"},
+		}
+
+		result := make([]Pair[testStruct, any], 0)
+		From(left).
+			FullOuterJoinSlice(right).
+			On("Id").
+			AsPairs().
+			AndAssignToSlice(&result)
+
+		// All items from both sides should be present
+		if len(result) != 4 {
+			t.Errorf("Expected 4 items but got %v", len(result))
+		}
+
+		leftOnlyCount := 0
+		rightOnlyCount := 0
+
+		for _, pair := range result {
+			if pair.Left.Id != 0 && pair.Right == nil {
+				leftOnlyCount++
+			} else if pair.Left.Id == 0 && pair.Right != nil {
+				rightOnlyCount++
+			}
+		}
+
+		if leftOnlyCount != 2 {
+			t.Errorf("Expected 2 left-only items but got %v", leftOnlyCount)
+		}
+
+		if rightOnlyCount != 2 {
+			t.Errorf("Expected 2 right-only items but got %v", rightOnlyCount)
+		}
+	})
+
+	t.Run("empty left side", func(t *testing.T) {
+		left := []testStruct{}
+		right := []testStruct{
+			{Id: 1, Name: "Right 1"},
+			{Id: 2, Name: "Right 2"},
+		}
+
+		result := make([]Pair[testStruct, any], 0)
+		From(left).
+			FullOuterJoinSlice(right).
+			On("Id").
+			AsPairs().
+			AndAssignToSlice(&result)
+
+		// Should have all right items with zero-value left
+		if len(result) != 2 {
+			t.Errorf("Expected 2 items but got %v", len(result))
+		}
+
+		for _, pair := range result {
+			if pair.Left.Id != 0 {
+				t.Errorf("Expected zero-value left but got %v", pair.Left)
+			}
+		}
+	})
+
+	t.Run("empty right side", func(t *testing.T) {
+		left := []testStruct{
+			{Id: 1, Name: "Left 1"},
+			{Id: 2, Name: "Left 2"},
+		}
+		right := []testStruct{}
+
+		result := make([]Pair[testStruct, any], 0)
+		From(left).
+			FullOuterJoinSlice(right).
+			On("Id").
+			AsPairs().
+			AndAssignToSlice(&result)
+
+		// Should have all left items with nil right
+		if len(result) != 2 {
+			t.Errorf("Expected 2 items but got %v", len(result))
+		}
+
+		for _, pair := range result {
+			if pair.Right != nil {
+				t.Errorf("Expected nil right but got %v", pair.Right)
+			}
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestSemiJoin(t *testing.T) {
+
+	left := []testStruct{
+		{Id: 1, Name: "Left 1"},
+		{Id: 2, Name: "Left 2"},
+		{Id: 3, Name: "Left 3"},
+	}
+	right := []testStruct{
+		{Id: 1, Name: "Right 1a"},
+		{Id: 1, Name: "Right 1b"},
+		{Id: 3, Name: "Right 3"},
+	}
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("left items", func(t *testing.T) {
+
+		result := make([]testStruct, 0)
+		From(left).
+			SemiJoinSlice(right).
+			On("Id").
+			AsLeftItems().
+			AndAssignToSlice(&result)
+
+		// Left 1 has two matches but is only yielded once
+		expected := []testStruct{left[0], left[2]}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("pairs", func(t *testing.T) {
+
+		result := make([]Pair[testStruct, any], 0)
+		From(left).
+			SemiJoin(From(right).AsAny()).
+			On("Id").
+			AsPairs().
+			AndAssignToSlice(&result)
+
+		if len(result) != 2 {
+			t.Fatalf("Expected 2 items but got %v", len(result))
+		}
+
+		for _, pair := range result {
+			if pair.Right != nil {
+				t.Errorf("Expected nil right but got %v", pair.Right)
+			}
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestAntiJoin(t *testing.T) {
+
+	left := []testStruct{
+		{Id: 1, Name: "Left 1"},
+		{Id: 2, Name: "Left 2"},
+		{Id: 3, Name: "Left 3"},
+		{Id: 4, Name: "Left 4"},
+	}
+	right := []testStruct{
+		{Id: 1, Name: "Right 1a"},
+		{Id: 1, Name: "Right 1b"},
+		{Id: 3, Name: "Right 3"},
+	}
+
+	result := make([]testStruct, 0)
+	From(left).
+		AntiJoinSlice(right).
+		On("Id").
+		AsLeftItems().
+		AndAssignToSlice(&result)
+
+	expected := []testStruct{left[1], left[3]}
+	if !slices.Equal(result, expected) {
+		t.Errorf("Expected %v but got %v", expected, result)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestJoinUnmatchedTracking(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("duplicate right items", func(t *testing.T) {
+
+		left := []testStruct{
+			{Id: 1, Name: "Left 1"},
+		}
+		right := []testStruct{
+			{Id: 1, Name: "Right 1"},
+			{Id: 1, Name: "Right 1"},
+			{Id: 2, Name: "Right 2"},
+			{Id: 2, Name: "Right 2"},
+		}
+
+		result := make([]OptionalPair[testStruct, any], 0)
+		From(left).
+			FullOuterJoinSlice(right).
+			On("Id").
+			AsOptionalPairs().
+			AndAssignToSlice(&result)
+
+		// Both duplicates are matched and both unmatched duplicates are kept
+		if len(result) != 4 {
+			t.Fatalf("Expected 4 items but got %v", len(result))
+		}
+
+		matchedCount := 0
+		rightOnlyCount := 0
+		for _, pair := range result {
+			if pair.Left.HasValue() {
+				matchedCount++
+			} else if pair.Right.Value().(testStruct).Id == 2 {
+				rightOnlyCount++
+			}
+		}
+
+		if matchedCount != 2 {
+			t.Errorf("Expected 2 matched items but got %v", matchedCount)
+		}
+
+		if rightOnlyCount != 2 {
+			t.Errorf("Expected 2 right-only items but got %v", rightOnlyCount)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("non-comparable right items", func(t *testing.T) {
+
+		type taggedStruct struct {
+			Id   int
+			Tags []string
+		}
+
+		left := []taggedStruct{
+			{Id: 1, Tags: []string{"a"}},
+		}
+		right := []taggedStruct{
+			{Id: 1, Tags: []string{"b"}},
+			{Id: 2, Tags: []string{"c"}},
+		}
+
+		result := make([]OptionalPair[taggedStruct, any], 0)
+		From(left).
+			RightJoinSlice(right).
+			On("Id").
+			AsOptionalPairs().
+			AndAssignToSlice(&result)
+
+		if len(result) != 2 {
+			t.Fatalf("Expected 2 items but got %v", len(result))
+		}
+
+		if result[0].Right.Value().(taggedStruct).Tags[0] != "b" {
+			t.Errorf("Expected matched right item b but got %v", result[0].Right)
+		}
+
+		if result[1].Left.HasValue() || result[1].Right.Value().(taggedStruct).Tags[0] != "c" {
+			t.Errorf("Expected unmatched right item c but got %v and %v", result[1].Left, result[1].Right)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestJoinOrdering(t *testing.T) {
+
+	left := []testStruct{
+		{Id: 5, Name: "Left 5"},
+		{Id: 1, Name: "Left 1"},
+		{Id: 9, Name: "Left 9"},
+	}
+	right := make([]testStruct, 0)
+	for id := 20; id > 0; id-- {
+		right = append(right, testStruct{Id: id, Name: fmt.Sprintf("Right %d", id)})
+	}
+	right = append(right, testStruct{Id: 1, Name: "Right 1b"})
+
+	// Matches follow their left item in right-side order, then the unmatched
+	// right items follow in right-side order
+	expected := []string{"Left 5/Right 5", "Left 1/Right 1", "Left 1/Right 1b", "Left 9/Right 9"}
+	for id := 20; id > 0; id-- {
+		if id != 1 && id != 5 && id != 9 {
+			expected = append(expected, fmt.Sprintf("/Right %d", id))
+		}
+	}
+
+	query := From(left).
+		FullOuterJoinSlice(right).
+		On("Id").
+		AsOptionalThis(func(left Optional[testStruct], right Optional[any]) any {
+			return left.OrElse(testStruct{}).Name + "/" + right.OrElse(testStruct{}).(testStruct).Name
+		})
+
+	for range 10 {
+		result := make([]string, 0)
+		query.AndAssignToSlice(&result)
+
+		if !slices.Equal(result, expected) {
+			t.Fatalf("Expected %v but got %v", expected, result)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestJoinLaziness(t *testing.T) {
+
+	left := []testStruct{
+		{Id: 1, Name: "Left 1"},
+		{Id: 2, Name: "Left 2"},
+	}
+	right := []testStruct{
+		{Id: 1, Name: "Right 1"},
+	}
+
+	rightReads := 0
+	rightIterable := Iterable[testStruct]{
+		Seq: func(yield func(testStruct) bool) {
+			for _, item := range right {
+				rightReads++
+				if !yield(item) {
+					return
+				}
+			}
+		},
+	}
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("deferred join", func(t *testing.T) {
+
+		rightReads = 0
+		query := From(left).
+			Join(rightIterable.AsAny()).
+			On("Id").
+			AsPairs()
+
+		if rightReads != 0 {
+			t.Errorf("Expected no right reads before iteration but got %v", rightReads)
+		}
+
+		result := make([]Pair[testStruct, any], 0)
+		query.AndAssignToSlice(&result)
+
+		if rightReads != 1 {
+			t.Errorf("Expected 1 right read but got %v", rightReads)
+		}
+
+		if len(result) != 1 {
+			t.Errorf("Expected 1 item but got %v", len(result))
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("typed join", func(t *testing.T) {
+
+		rightReads = 0
+		query := JoinOn(
+			From(left),
+			rightIterable,
+			func(left testStruct) int { return left.Id },
+			func(right testStruct) int { return right.Id },
+		)
+
+		if rightReads != 0 {
+			t.Errorf("Expected no right reads before iteration but got %v", rightReads)
+		}
+
+		for range query.Seq {
+		}
+
+		if rightReads != 1 {
+			t.Errorf("Expected 1 right read but got %v", rightReads)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("rebuilt per iteration", func(t *testing.T) {
+
+		rightReads = 0
+		query := From(left).
+			Join(rightIterable.AsAny()).
+			On("Id").
+			AsPairs()
+
+		first := make([]Pair[testStruct, any], 0)
+		query.AndAssignToSlice(&first)
+
+		// The right side changes between iterations
+		right = append(right, testStruct{Id: 2, Name: "Right 2"})
+
+		second := make([]Pair[testStruct, any], 0)
+		query.AndAssignToSlice(&second)
+
+		if len(first) != 1 {
+			t.Errorf("Expected 1 item in first iteration but got %v", len(first))
+		}
+
+		if len(second) != 2 {
+			t.Errorf("Expected 2 items in second iteration but got %v", len(second))
+		}
+
+		if rightReads != 3 {
+			t.Errorf("Expected 3 right reads but got %v", rightReads)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+//----------------------------------------------------------------------------//
+// Typed Joining                                                              //
+//----------------------------------------------------------------------------//
+
+////////////////////////////////////////////////////////////////////////////////
+
+type testOrder struct {
+	Id         int
+	CustomerId int
+	Item       string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestJoinOn(t *testing.T) {
+
+	customers := []testStruct{
+		{Id: 1, Name: "Customer 1"},
+		{Id: 2, Name: "Customer 2"},
+		{Id: 3, Name: "Customer 3"},
+	}
+	orders := []testOrder{
+		{Id: 10, CustomerId: 1, Item: "Apple"},
+		{Id: 11, CustomerId: 2, Item: "Banana"},
+		{Id: 12, CustomerId: 1, Item: "Cherry"},
+		{Id: 13, CustomerId: 4, Item: "Durian"},
+	}
+
+	result := make([]Pair[testStruct, testOrder], 0)
+	for pair := range JoinOn(
+		From(customers),
+		From(orders),
+		func(customer testStruct) int { return customer.Id },
+		func(order testOrder) int { return order.CustomerId },
+	).Seq {
+		result = append(result, pair)
+	}
+
+	if len(result) != 3 {
+		t.Fatalf("Expected 3 items but got %v", len(result))
+	}
+
+	if result[0].Left.Name != "Customer 1" || result[0].Right.Item != "Apple" {
+		t.Errorf("Expected Customer 1 and Apple but got %v and %v", result[0].Left.Name, result[0].Right.Item)
+	}
+
+	if result[1].Left.Name != "Customer 1" || result[1].Right.Item != "Cherry" {
+		t.Errorf("Expected Customer 1 and Cherry but got %v and %v", result[1].Left.Name, result[1].Right.Item)
+	}
+
+	if result[2].Left.Name != "Customer 2" || result[2].Right.Item != "Banana" {
+		t.Errorf("Expected Customer 2 and Banana but got %v and %v", result[2].Left.Name, result[2].Right.Item)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestLeftJoinOn(t *testing.T) {
+
+	customers := []testStruct{
+		{Id: 1, Name: "Customer 1"},
+		{Id: 3, Name: "Customer 3"},
+	}
+	orders := []testOrder{
+		{Id: 10, CustomerId: 1, Item: "Apple"},
+		{Id: 11, CustomerId: 2, Item: "Banana"},
+	}
+
+	result := make([]Pair[testStruct, Optional[testOrder]], 0)
+	for pair := range LeftJoinOn(
+		From(customers),
+		From(orders),
+		func(customer testStruct) int { return customer.Id },
+		func(order testOrder) int { return order.CustomerId },
+	).Seq {
+		result = append(result, pair)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 items but got %v", len(result))
+	}
+
+	if result[0].Left.Id != 1 || result[0].Right.Value().Item != "Apple" {
+		t.Errorf("Expected Customer 1 and Apple but got %v and %v", result[0].Left, result[0].Right)
+	}
+
+	if result[1].Left.Id != 3 || result[1].Right.HasValue() {
+		t.Errorf("Expected Customer 3 and no order but got %v and %v", result[1].Left, result[1].Right)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestRightJoinOn(t *testing.T) {
+
+	customers := []testStruct{
+		{Id: 1, Name: "Customer 1"},
+		{Id: 3, Name: "Customer 3"},
+	}
+	orders := []testOrder{
+		{Id: 10, CustomerId: 1, Item: "Apple"},
+		{Id: 11, CustomerId: 2, Item: "Banana"},
+	}
+
+	result := make([]Pair[Optional[testStruct], testOrder], 0)
+	for pair := range RightJoinOn(
+		From(customers),
+		From(orders),
+		func(customer testStruct) int { return customer.Id },
+		func(order testOrder) int { return order.CustomerId },
+	).Seq {
+		result = append(result, pair)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 items but got %v", len(result))
+	}
+
+	if result[0].Left.Value().Id != 1 || result[0].Right.Item != "Apple" {
+		t.Errorf("Expected Customer 1 and Apple but got %v and %v", result[0].Left, result[0].Right)
+	}
+
+	if result[1].Left.HasValue() || result[1].Right.Item != "Banana" {
+		t.Errorf("Expected no customer and Banana but got %v and %v", result[1].Left, result[1].Right)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestFullOuterJoinOn(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("generic", func(t *testing.T) {
+
+		customers := []testStruct{
+			{Id: 1, Name: "Customer 1"},
+			{Id: 3, Name: "Customer 3"},
+		}
+		orders := []testOrder{
+			{Id: 10, CustomerId: 1, Item: "Apple"},
+			{Id: 11, CustomerId: 2, Item: "Banana"},
+		}
+
+		result := make([]OptionalPair[testStruct, testOrder], 0)
+		for pair := range FullOuterJoinOn(
+			From(customers),
+			From(orders),
+			func(customer testStruct) int { return customer.Id },
+			func(order testOrder) int { return order.CustomerId },
+		).Seq {
+			result = append(result, pair)
+		}
+
+		if len(result) != 3 {
+			t.Fatalf("Expected 3 items but got %v", len(result))
+		}
+
+		if result[0].Left.Value().Id != 1 || result[0].Right.Value().Item != "Apple" {
+			t.Errorf("Expected Customer 1 and Apple but got %v and %v", result[0].Left, result[0].Right)
+		}
+
+		if result[1].Left.Value().Id != 3 || result[1].Right.HasValue() {
+			t.Errorf("Expected Customer 3 and no order but got %v and %v", result[1].Left, result[1].Right)
+		}
+
+		if result[2].Left.HasValue() || result[2].Right.Value().Item != "Banana" {
+			t.Errorf("Expected no customer and Banana but got %v and %v", result[2].Left, result[2].Right)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("zero valued items", func(t *testing.T) {
+
+		left := []int{0, 1}
+		right := []int{0, 2}
+
+		result := make([]OptionalPair[int, int], 0)
+		for pair := range FullOuterJoinOn(
+			From(left),
+			From(right),
+			func(left int) int { return left },
+			func(right int) int { return right },
+		).Seq {
+			result = append(result, pair)
+		}
+
+		if len(result) != 3 {
+			t.Fatalf("Expected 3 items but got %v", len(result))
+		}
+
+		// The zero-valued items matched each other
+		if !result[0].Left.HasValue() || !result[0].Right.HasValue() {
+			t.Errorf("Expected matched zero values but got %v and %v", result[0].Left, result[0].Right)
+		}
+
+		if result[1].Left.Value() != 1 || result[1].Right.HasValue() {
+			t.Errorf("Expected 1 and no right but got %v and %v", result[1].Left, result[1].Right)
+		}
+
+		if result[2].Left.HasValue() || result[2].Right.Value() != 2 {
+			t.Errorf("Expected no left and 2 but got %v and %v", result[2].Left, result[2].Right)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestGroupJoinOn(t *testing.T) {
+
+	customers := []testStruct{
+		{Id: 1, Name: "Customer 1"},
+		{Id: 2, Name: "Customer 2"},
+	}
+	orders := []testOrder{
+		{Id: 10, CustomerId: 1, Item: "Apple"},
+		{Id: 11, CustomerId: 3, Item: "Banana"},
+		{Id: 12, CustomerId: 1, Item: "Cherry"},
+	}
+
+	result := make([]Pair[testStruct, []testOrder], 0)
+	for pair := range GroupJoinOn(
+		From(customers),
+		From(orders),
+		func(customer testStruct) int { return customer.Id },
+		func(order testOrder) int { return order.CustomerId },
+	).Seq {
+		result = append(result, pair)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 items but got %v", len(result))
+	}
+
+	if result[0].Left.Id != 1 || len(result[0].Right) != 2 {
+		t.Fatalf("Expected Customer 1 with 2 orders but got %v", result[0])
+	}
+
+	if result[0].Right[0].Item != "Apple" || result[0].Right[1].Item != "Cherry" {
+		t.Errorf("Expected Apple and Cherry but got %v", result[0].Right)
+	}
+
+	if result[1].Left.Id != 2 || len(result[1].Right) != 0 {
+		t.Errorf("Expected Customer 2 with no orders but got %v", result[1])
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestSemiJoinOn(t *testing.T) {
+
+	customers := []testStruct{
+		{Id: 1, Name: "Customer 1"},
+		{Id: 2, Name: "Customer 2"},
+	}
+	orders := []testOrder{
+		{Id: 10, CustomerId: 1, Item: "Apple"},
+		{Id: 11, CustomerId: 1, Item: "Banana"},
+	}
+
+	result := make([]testStruct, 0)
+	for customer := range SemiJoinOn(
+		From(customers),
+		From(orders),
+		func(customer testStruct) int { return customer.Id },
+		func(order testOrder) int { return order.CustomerId },
+	).Seq {
+		result = append(result, customer)
+	}
+
+	expected := []testStruct{customers[0]}
+	if !slices.Equal(result, expected) {
+		t.Errorf("Expected %v but got %v", expected, result)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestAntiJoinOn(t *testing.T) {
+
+	customers := []testStruct{
+		{Id: 1, Name: "Customer 1"},
+		{Id: 2, Name: "Customer 2"},
+	}
+	orders := []testOrder{
+		{Id: 10, CustomerId: 1, Item: "Apple"},
+		{Id: 11, CustomerId: 1, Item: "Banana"},
+	}
+
+	result := make([]testStruct, 0)
+	for customer := range AntiJoinOn(
+		From(customers),
+		From(orders),
+		func(customer testStruct) int { return customer.Id },
+		func(order testOrder) int { return order.CustomerId },
+	).Seq {
+		result = append(result, customer)
+	}
+
+	expected := []testStruct{customers[1]}
+	if !slices.Equal(result, expected) {
+		t.Errorf("Expected %v but got %v", expected, result)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestCompositeKeySelectors(t *testing.T) {
+
+	type sale struct {
+		Year  int
+		Month int
+		Total int
+	}
+	type target struct {
+		TargetYear  int
+		TargetMonth int
+		Goal        int
+	}
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("generic", func(t *testing.T) {
+
+		sales := []sale{
+			{Year: 2024, Month: 1, Total: 10},
+			{Year: 2024, Month: 2, Total: 20},
+		}
+		targets := []target{
+			{TargetYear: 2024, TargetMonth: 2, Goal: 15},
+			{TargetYear: 2023, TargetMonth: 1, Goal: 5},
+		}
+
+		leftKeySelector, rightKeySelector := CompositeKeySelectors[sale, target](
+			[]string{"Year", "Month"},
+			[]string{"TargetYear", "TargetMonth"},
+		)
+
+		result := make([]Pair[sale, target], 0)
+		for pair := range JoinOn(From(sales), From(targets), leftKeySelector, rightKeySelector).Seq {
+			result = append(result, pair)
+		}
+
+		if len(result) != 1 || result[0].Left.Total != 20 || result[0].Right.Goal != 15 {
+			t.Errorf("Expected one match of 20 and 15 but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("arity mismatch", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		CompositeKeySelectors[sale, target]([]string{"Year", "Month"}, []string{"TargetYear"})
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("type mismatch", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		CompositeKeySelectors[sale, testStruct]([]string{"Year", "Month"}, []string{"Id", "Name"})
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestCrossJoinOf(t *testing.T) {
+
+	result := make([]Pair[string, int], 0)
+	for pair := range CrossJoinOf(From([]string{"A", "B"}), From([]int{1, 2})).Seq {
+		result = append(result, pair)
+	}
+
+	expected := []Pair[string, int]{
+		{Left: "A", Right: 1},
+		{Left: "A", Right: 2},
+		{Left: "B", Right: 1},
+		{Left: "B", Right: 2},
+	}
+	if !slices.Equal(result, expected) {
+		t.Errorf("Expected %v but got %v", expected, result)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestProduct(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("generic", func(t *testing.T) {
+
+		result := make([]string, 0)
+		for combination := range Product(
+			From([]string{"a", "b"}),
+			From([]string{"1", "2"}),
+			From([]string{"x", "y", "z"}),
+		).Seq {
+			result = append(result, strings.Join(combination, ""))
+		}
+
+		expected := []string{
+			"a1x", "a1y", "a1z", "a2x", "a2y", "a2z",
+			"b1x", "b1y", "b1z", "b2x", "b2y", "b2z",
+		}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("single", func(t *testing.T) {
+
+		result := make([][]int, 0)
+		for combination := range Product(From([]int{1, 2})).Seq {
+			result = append(result, combination)
+		}
+
+		if len(result) != 2 || result[0][0] != 1 || result[1][0] != 2 {
+			t.Errorf("Expected [[1] [2]] but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("empty", func(t *testing.T) {
+
+		for combination := range Product(From([]int{1, 2}), From([]int{})).Seq {
+			t.Errorf("Expected no items but got %v", combination)
+		}
+
+		for combination := range Product[int]().Seq {
+			t.Errorf("Expected no items but got %v", combination)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("early termination", func(t *testing.T) {
+
+		count := 0
+		for range Product(From([]int{1, 2}), From([]int{1, 2})).Seq {
+			count++
+			if count == 3 {
+				break
+			}
+		}
+
+		if count != 3 {
+			t.Errorf("Expected 3 items but got %v", count)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestJoinOnEarlyTermination(t *testing.T) {
+
+	left := []testStruct{
+		{Id: 1, Name: "Left 1"},
+		{Id: 2, Name: "Left 2"},
+	}
+	right := []testOrder{
+		{Id: 10, CustomerId: 1},
+		{Id: 11, CustomerId: 3},
+	}
+
+	count := 0
+	for range FullOuterJoinOn(
+		From(left),
+		From(right),
+		func(left testStruct) int { return left.Id },
+		func(right testOrder) int { return right.CustomerId },
+	).Seq {
+		count++
+		break
+	}
+
+	if count != 1 {
+		t.Errorf("Expected 1 item but got %v", count)
+	}
 }
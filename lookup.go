@@ -0,0 +1,226 @@
+package weaklinq
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+)
+
+//----------------------------------------------------------------------------//
+// Lookup                                                                     //
+//----------------------------------------------------------------------------//
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Lookup is a typed, read-only result of grouping, mapping each key to the
+// values grouped under it. Keys are kept in the order they were first seen.
+// Unlike AndAssignToMap, a Lookup needs no reflection to be read from.
+type Lookup[K comparable, V any] struct {
+	keys   []K
+	groups map[K][]V
+}
+
+/////////////////////////////////////////////////////////////////////////////////
+
+// Grouping is a key and the values grouped under it, as yielded by GroupBy.
+type Grouping[K any, V any] struct {
+	Key    K
+	Values []V
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// newLookup returns an empty Lookup ready to be added to.
+func newLookup[K comparable, V any]() Lookup[K, V] {
+
+	return Lookup[K, V]{
+		groups: make(map[K][]V),
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// add adds the given value under the given key.
+func (lookup *Lookup[K, V]) add(key K, value V) {
+
+	values, exists := lookup.groups[key]
+	if !exists {
+		lookup.keys = append(lookup.keys, key)
+	}
+
+	lookup.groups[key] = append(values, value)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Get returns a copy of the values grouped under the given key, or nil if the
+// key is not in the Lookup.
+func (lookup Lookup[K, V]) Get(key K) []V {
+
+	return slices.Clone(lookup.groups[key])
+
+	/*
+		values := lookup.Get(key)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Contains returns whether the given key is in the Lookup.
+func (lookup Lookup[K, V]) Contains(key K) bool {
+
+	_, exists := lookup.groups[key]
+	return exists
+
+	/*
+		if lookup.Contains(key) {
+			...
+		}
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Keys returns the keys of the Lookup in the order they were first seen.
+func (lookup Lookup[K, V]) Keys() []K {
+
+	return slices.Clone(lookup.keys)
+
+	/*
+		keys := lookup.Keys()
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Len returns the number of keys in the Lookup.
+func (lookup Lookup[K, V]) Len() int {
+
+	return len(lookup.keys)
+
+	/*
+		count := lookup.Len()
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// All returns an iterator over the keys of the Lookup and copies of the values
+// grouped under them, in the order the keys were first seen.
+func (lookup Lookup[K, V]) All() iter.Seq2[K, []V] {
+
+	return func(yield func(K, []V) bool) {
+		for _, key := range lookup.keys {
+			if !yield(key, slices.Clone(lookup.groups[key])) {
+				return
+			}
+		}
+	}
+
+	/*
+		for key, values := range lookup.All() {
+			...
+		}
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// ToLookup iterates over the MapIterable and returns its groups as a Lookup,
+// in the order yielded by Groups. If the MapIterable is set to overwrite, each
+// key holds only one value, as decided by its conflict policy. Groups that
+// fail a HavingThis predicate are left out. If the key was grouped by several
+// field names, K may be a struct with fields of the same names, as with
+// AndAssignToMap. A nil key or value is read as the zero K or V if that can be
+// nil. If a key is not a K or a value is not a V, this function will panic.
+func ToLookup[K comparable, V any, T any](iterable MapIterable[T]) Lookup[K, V] {
+
+	lookup := newLookup[K, V]()
+	for rawKey, rawValue := range iterable.Groups() {
+
+		if rawKey != nil && len(iterable.thenKeySelectors) == 0 {
+			rawKey = iterable.mapKey(reflect.TypeFor[K](), 0, rawKey).Interface()
+		}
+
+		key, ok := asType[K](rawKey)
+		if !ok {
+			panic(fmt.Sprintf("group key %v is %T, not %v", rawKey, rawKey, reflect.TypeFor[K]()))
+		}
+
+		rawValues := []any{rawValue}
+		if !iterable.overwrite || len(iterable.reducers) > 0 {
+			rawValues = rawValue.([]any)
+		}
+
+		for _, rawValue := range rawValues {
+			value, ok := asType[V](rawValue)
+			if !ok {
+				panic(fmt.Sprintf("group value %v is %T, not %v", rawValue, rawValue, reflect.TypeFor[V]()))
+			}
+			lookup.add(key, value)
+		}
+	}
+
+	return lookup
+
+	/*
+		lookup := linq.ToLookup[K, V](
+			linq.From([]T{...}).
+				GroupListsOf("ItemField").
+				By("KeyField"),
+		)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// asType returns the given value as a V, and whether it is one. A nil value is
+// the zero V if V is a type that can be nil, such as an interface or pointer.
+func asType[V any](raw any) (V, bool) {
+
+	if value, ok := raw.(V); ok || raw != nil {
+		return value, ok
+	}
+
+	var zero V
+	switch reflect.TypeFor[V]().Kind() {
+	case reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return zero, true
+	}
+
+	return zero, false
+}
+
+////////
+
+// GroupBy returns a new Iterable of the items grouped by the given key
+// selector, as Groupings in the order their keys were first seen. Nothing is
+// grouped until the Iterable is iterated, and the items are grouped again
+// each time it is. The output can be used with any further Iterable operator.
+func GroupBy[T any, K comparable](iterable Iterable[T], keySelector func(T) K) Iterable[Grouping[K, T]] {
+
+	return Iterable[Grouping[K, T]]{
+		Seq: func(yield func(Grouping[K, T]) bool) {
+			lookup := newLookup[K, T]()
+			for item := range iterable.Seq {
+				lookup.add(keySelector(item), item)
+			}
+
+			for key, values := range lookup.All() {
+				if !yield(Grouping[K, T]{Key: key, Values: values}) {
+					return
+				}
+			}
+		},
+	}
+
+	/*
+		linq.GroupBy(
+			linq.From([]T{...}),
+			func(item T) K {
+				return item.KeyField
+			},
+		)
+	*/
+}
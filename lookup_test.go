@@ -0,0 +1,286 @@
+package weaklinq
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+//----------------------------------------------------------------------------//
+// Lookup                                                                     //
+//----------------------------------------------------------------------------//
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestLookup(t *testing.T) {
+
+	testItems := []testStruct{
+		{Id: 2, Name: "Test 2a"},
+		{Id: 1, Name: "Test 1"},
+		{Id: 2, Name: "Test 2b"},
+	}
+
+	lookup := ToLookup[int, string](From(testItems).GroupListsOf("Name").By("Id"))
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("get", func(t *testing.T) {
+
+		if values := lookup.Get(2); !slices.Equal(values, []string{"Test 2a", "Test 2b"}) {
+			t.Errorf("Expected [Test 2a Test 2b] but got %v", values)
+		}
+
+		if values := lookup.Get(3); values != nil {
+			t.Errorf("Expected nil but got %v", values)
+		}
+
+		lookup.Get(2)[0] = "Changed"
+		if values := lookup.Get(2); values[0] != "Test 2a" {
+			t.Errorf("Expected values to be unaffected but got %v", values)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("contains", func(t *testing.T) {
+
+		if !lookup.Contains(1) {
+			t.Errorf("Expected key 1 to be found")
+		}
+
+		if lookup.Contains(3) {
+			t.Errorf("Expected key 3 not to be found")
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("keys", func(t *testing.T) {
+
+		if keys := lookup.Keys(); !slices.Equal(keys, []int{2, 1}) {
+			t.Errorf("Expected [2 1] but got %v", keys)
+		}
+
+		if lookup.Len() != 2 {
+			t.Errorf("Expected 2 keys but got %v", lookup.Len())
+		}
+
+		keys := lookup.Keys()
+		keys[0] = 5
+		if !lookup.Contains(2) || lookup.Keys()[0] != 2 {
+			t.Errorf("Expected keys to be unaffected but got %v", lookup.Keys())
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("all", func(t *testing.T) {
+
+		keys := make([]int, 0)
+		counts := make([]int, 0)
+		for key, values := range lookup.All() {
+			keys = append(keys, key)
+			counts = append(counts, len(values))
+		}
+
+		if !slices.Equal(keys, []int{2, 1}) || !slices.Equal(counts, []int{2, 1}) {
+			t.Errorf("Expected keys [2 1] with counts [2 1] but got %v with %v", keys, counts)
+		}
+
+		for _, values := range lookup.All() {
+			values[0] = "Changed"
+		}
+		if values := lookup.Get(1); values[0] != "Test 1" {
+			t.Errorf("Expected values to be unaffected but got %v", values)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("overwrite", func(t *testing.T) {
+
+		result := ToLookup[int, string](From(testItems).Group("Name").By("Id"))
+
+		if values := result.Get(2); !slices.Equal(values, []string{"Test 2b"}) {
+			t.Errorf("Expected [Test 2b] but got %v", values)
+		}
+
+		if keys := result.Keys(); !slices.Equal(keys, []int{2, 1}) {
+			t.Errorf("Expected [2 1] but got %v", keys)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("empty", func(t *testing.T) {
+
+		var result Lookup[int, string]
+
+		if result.Len() != 0 || result.Contains(1) || result.Get(1) != nil {
+			t.Errorf("Expected empty lookup but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("nil values", func(t *testing.T) {
+
+		type result struct {
+			Id  int
+			Err error
+		}
+
+		failure := errors.New("failed")
+		results := []result{{Id: 1, Err: nil}, {Id: 1, Err: failure}}
+
+		lookup := ToLookup[int, error](From(results).GroupListsOf("Err").By("Id"))
+
+		if values := lookup.Get(1); len(values) != 2 || values[0] != nil || values[1] != failure {
+			t.Errorf("Expected [<nil> failed] but got %v", values)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("struct keys", func(t *testing.T) {
+
+		type sale struct {
+			Year   int
+			Month  int
+			Amount int
+		}
+
+		type period struct {
+			Year  int
+			Month int
+		}
+
+		sales := []sale{
+			{Year: 2024, Month: 1, Amount: 10},
+			{Year: 2024, Month: 2, Amount: 20},
+			{Year: 2024, Month: 1, Amount: 30},
+		}
+
+		lookup := ToLookup[period, sale](From(sales).GroupListsBy("Year", "Month"))
+
+		if keys := lookup.Keys(); !slices.Equal(keys, []period{{2024, 1}, {2024, 2}}) {
+			t.Errorf("Expected [{2024 1} {2024 2}] but got %v", keys)
+		}
+
+		if values := lookup.Get(period{2024, 1}); !slices.Equal(values, []sale{sales[0], sales[2]}) {
+			t.Errorf("Expected [%v %v] but got %v", sales[0], sales[2], values)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("key type mismatch", func(t *testing.T) {
+
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("Expected panic but got none")
+			}
+		}()
+
+		ToLookup[string, string](From(testItems).GroupListsOf("Name").By("Id"))
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("value type mismatch", func(t *testing.T) {
+
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("Expected panic but got none")
+			}
+		}()
+
+		ToLookup[int, int](From(testItems).GroupListsOf("Name").By("Id"))
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestGroupByGroupings(t *testing.T) {
+
+	testItems := []testStruct{
+		{Id: 2, Name: "Test 2a"},
+		{Id: 1, Name: "Test 1"},
+		{Id: 2, Name: "Test 2b"},
+	}
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("generic", func(t *testing.T) {
+
+		result := make([]Grouping[int, testStruct], 0)
+		for grouping := range GroupBy(From(testItems), func(item testStruct) int { return item.Id }).Seq {
+			result = append(result, grouping)
+		}
+
+		if len(result) != 2 {
+			t.Fatalf("Expected 2 groupings but got %v", len(result))
+		}
+
+		if result[0].Key != 2 || !slices.Equal(result[0].Values, []testStruct{testItems[0], testItems[2]}) {
+			t.Errorf("Expected key 2 with two items but got %v", result[0])
+		}
+
+		if result[1].Key != 1 || !slices.Equal(result[1].Values, []testStruct{testItems[1]}) {
+			t.Errorf("Expected key 1 with one item but got %v", result[1])
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("chained", func(t *testing.T) {
+
+		groupings := GroupBy(From(testItems), func(item testStruct) int { return item.Id })
+		result := make([]int, 0)
+		Select(
+			groupings.FilterOnThis(func(grouping Grouping[int, testStruct]) bool { return len(grouping.Values) > 1 }),
+			func(grouping Grouping[int, testStruct]) int { return grouping.Key },
+		).AndAssignToSlice(&result)
+
+		if !slices.Equal(result, []int{2}) {
+			t.Errorf("Expected [2] but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("lazy", func(t *testing.T) {
+
+		reads := 0
+		source := Iterable[int]{
+			Seq: func(yield func(int) bool) {
+				for i := range 4 {
+					reads++
+					if !yield(i) {
+						return
+					}
+				}
+			},
+		}
+
+		groupings := GroupBy(source, func(item int) bool { return item%2 == 0 })
+		if reads != 0 {
+			t.Errorf("Expected no reads before iterating but got %v", reads)
+		}
+
+		for range groupings.Seq {
+			break
+		}
+		for range groupings.Seq {
+			break
+		}
+
+		if reads != 8 {
+			t.Errorf("Expected 8 reads but got %v", reads)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+}
@@ -1,6 +1,9 @@
 package weaklinq
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+)
 
 //----------------------------------------------------------------------------//
 // Materialization                                                            //
@@ -13,7 +16,17 @@ import "reflect"
 // selector results in the MapIterable. If there is a key or value type mismatch,
 // or the result is not a pointer to a map, this function will panic. If the
 // MapIterable is set to overwrite, this function will attempt to build a slice
-// of the value type, or append to the value if it already exists.
+// of the value type, or append to the value if it already exists. If the
+// MapIterable is set to overwrite and a key comes up more than once in the
+// items, its value is decided by the conflict policy set with OnConflict or
+// MergeConflictsThis. Values already in the map are overwritten regardless.
+// If the MapIterable has reducers, each value is built from the reducer
+// results as described in Aggregate, replacing any value already in the map.
+// If it has a HavingThis predicate, groups that fail it are left out of the
+// map. If a key level was grouped by several field names, its map key may be
+// a struct with fields of the same names, which are filled in by name. If the
+// MapIterable has ThenBy key levels, the result must be a map of maps nested
+// one level per key, such as map[K1]map[K2][]V, or this function will panic.
 func (iterable MapIterable[T]) AndAssignToMap(result any) {
 
 	res := reflect.ValueOf(result)
@@ -27,19 +40,79 @@ func (iterable MapIterable[T]) AndAssignToMap(result any) {
 	}
 
 	m := reflect.Indirect(res)
+	levels := 1 + len(iterable.thenKeySelectors)
+	leafIsSlice := !iterable.overwrite && len(iterable.reducers) == 0
+	var itemType reflect.Type
+	if iterable.overwrite && len(iterable.reducers) == 0 && !iterable.selectsItems {
+		itemType = reflect.TypeFor[T]()
+	}
+	validateMapLevels(m.Type(), levels, leafIsSlice, itemType)
+
+	// Selected values are only known once there is an item to select from, so
+	// their type is checked against the map on the first one.
+	checkSelected := iterable.overwrite && len(iterable.reducers) == 0 && iterable.selectsItems
+	checkSelectedValue := func(value any) {
+		if checkSelected {
+			validateMapLevels(m.Type(), levels, false, reflect.TypeOf(value))
+			checkSelected = false
+		}
+	}
+
+	// Aggregates and filtered groups are only known once every item has been
+	// seen, so they are assigned from Groups rather than item by item.
+	if len(iterable.reducers) > 0 || len(iterable.havingPredicates) > 0 {
+		for key, value := range iterable.Groups() {
+			levelKeys := splitGroupKey(key, levels)
+			leafMap := iterable.nestedMap(m, levelKeys[:levels-1])
+			leafKey := iterable.mapKey(leafMap.Type().Key(), levels-1, levelKeys[levels-1])
+			existingValue := leafMap.MapIndex(leafKey)
+
+			switch {
+			case len(iterable.reducers) > 0:
+				leafMap.SetMapIndex(leafKey, iterable.groupValue(leafMap.Type().Elem(), value))
 
+			case iterable.overwrite:
+				checkSelectedValue(value)
+				leafMap.SetMapIndex(leafKey, reflect.ValueOf(value))
+
+			default:
+				appendedSlice := iterable.groupValue(leafMap.Type().Elem(), value)
+				if existingValue.IsValid() {
+					appendedSlice = reflect.AppendSlice(existingValue, appendedSlice)
+				}
+				leafMap.SetMapIndex(leafKey, appendedSlice)
+			}
+		}
+
+		res.Elem().Set(m)
+		return
+	}
+
+	// Conflicts are only between items, so values already in the map are
+	// overwritten as usual.
+	seenKeys := make(map[any]bool)
 	for item := range iterable.itemIterable.Seq {
 
-		key := iterable.keySelector(item)
+		key := iterable.groupKey(item)
 		val := iterable.itemSelector(item)
-		reflectKey := reflect.ValueOf(key)
+		if iterable.overwrite {
+			checkSelectedValue(val)
+		}
+		levelKeys := splitGroupKey(key, levels)
+		leafMap := iterable.nestedMap(m, levelKeys[:levels-1])
+		reflectKey := iterable.mapKey(leafMap.Type().Key(), levels-1, levelKeys[levels-1])
 		reflectVal := reflect.ValueOf(val)
 
 		if iterable.overwrite {
-			m.SetMapIndex(reflectKey, reflectVal)
+			if seenKeys[key] {
+				resolved := iterable.resolveConflict(key, leafMap.MapIndex(reflectKey).Interface(), val)
+				reflectVal = reflect.ValueOf(resolved)
+			}
+			seenKeys[key] = true
+			leafMap.SetMapIndex(reflectKey, reflectVal)
 
 		} else {
-			existingValue := m.MapIndex(reflectKey)
+			existingValue := leafMap.MapIndex(reflectKey)
 
 			if !existingValue.IsValid() {
 				sliceType := reflect.SliceOf(reflect.TypeOf(val))
@@ -48,7 +121,7 @@ func (iterable MapIterable[T]) AndAssignToMap(result any) {
 			}
 
 			appendedSlice := reflect.Append(existingValue, reflect.ValueOf(val))
-			m.SetMapIndex(reflectKey, appendedSlice)
+			leafMap.SetMapIndex(reflectKey, appendedSlice)
 		}
 	}
 
@@ -68,6 +141,147 @@ func (iterable MapIterable[T]) AndAssignToMap(result any) {
 			GroupListsOf("ItemField").
 			By("KeyField").
 			AndAssignToMap(&result)
+
+		// Nested
+		result := make(map[K1]map[K2][]V)
+		linq.From([]T{...}).
+			GroupListsOf("ItemField").
+			By("KeyField1").
+			ThenBy("KeyField2").
+			AndAssignToMap(&result)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// groupValue returns a value yielded by Groups as a value of the given type.
+// Aggregates are built as described in Aggregate, and lists are built into a
+// slice of the given type.
+func (iterable MapIterable[T]) groupValue(valueType reflect.Type, value any) reflect.Value {
+
+	switch {
+	case len(iterable.reducers) > 0:
+		return aggregateValue(valueType, iterable.reducers, value.([]any))
+
+	case iterable.overwrite:
+		return reflect.ValueOf(value)
+	}
+
+	items := value.([]any)
+	slice := reflect.MakeSlice(valueType, 0, len(items))
+	for _, item := range items {
+		slice = reflect.Append(slice, reflect.ValueOf(item))
+	}
+
+	return slice
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// validateMapLevels checks that the given map type has a map value for each
+// of its key levels but the last, and a slice value at the last level if
+// leafIsSlice is set. A map value at the last level is only allowed if the
+// given item type is a map or an interface, so that a map nested too deeply is
+// caught as well. If itemType is nil, the last level is not checked for a map.
+// If the map type does not match, this function will panic.
+func validateMapLevels(mapType reflect.Type, levels int, leafIsSlice bool, itemType reflect.Type) {
+
+	valueType := mapType
+	for level := 1; level < levels; level++ {
+		valueType = valueType.Elem()
+		if valueType.Kind() != reflect.Map {
+			panic(fmt.Sprintf("'result' must be a map nested %d levels deep, but is %v", levels, mapType))
+		}
+	}
+
+	leafType := valueType.Elem()
+	if leafIsSlice && leafType.Kind() == reflect.Map {
+		panic(fmt.Sprintf("'result' must be a map nested %d levels deep, but is %v", levels, mapType))
+	}
+
+	if itemType != nil && leafType.Kind() == reflect.Map && itemType.Kind() != reflect.Map && itemType.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("'result' must be a map nested %d levels deep, but is %v", levels, mapType))
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// nestedMap returns the map reached by following the given keys down from the
+// given map, creating any missing maps on the way.
+func (iterable MapIterable[T]) nestedMap(m reflect.Value, keys []any) reflect.Value {
+
+	for level, key := range keys {
+		reflectKey := iterable.mapKey(m.Type().Key(), level, key)
+		inner := m.MapIndex(reflectKey)
+		if !inner.IsValid() || inner.IsNil() {
+			inner = reflect.MakeMap(m.Type().Elem())
+			m.SetMapIndex(reflectKey, inner)
+		}
+		m = inner
+	}
+
+	return m
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AndAssignToOrderedSlice assigns the result of iterating over the MapIterable
+// to a slice of KeyValues, with one KeyValue per key in the order the keys
+// were first seen. The Key and Value types must match the key and value
+// selector results as they would for AndAssignToMap, so the Value is a slice
+// if the MapIterable is not set to overwrite, and is built as described in
+// Aggregate if it has reducers. If there is a type mismatch, or the result is
+// not a pointer to a slice of KeyValues, this function will panic.
+func (iterable MapIterable[T]) AndAssignToOrderedSlice(result any) {
+
+	res := reflect.ValueOf(result)
+	if res.Kind() != reflect.Pointer || res.IsNil() {
+		panic("'result' must be a pointer to a slice of KeyValues")
+	}
+
+	sliceValue := reflect.Indirect(res)
+	if sliceValue.Kind() != reflect.Slice || sliceValue.Type().Elem().Kind() != reflect.Struct {
+		panic("'result' must be a pointer to a slice of KeyValues")
+	}
+
+	keyValueType := sliceValue.Type().Elem()
+	keyField, hasKey := keyValueType.FieldByName("Key")
+	valueField, hasValue := keyValueType.FieldByName("Value")
+	if !hasKey || !hasValue {
+		panic("'result' must be a pointer to a slice of KeyValues")
+	}
+
+	s := reflect.Indirect(res)
+
+	for key, value := range iterable.Groups() {
+
+		keyValue := reflect.New(keyValueType).Elem()
+		reflectKey := reflect.ValueOf(key)
+		if len(iterable.thenKeySelectors) == 0 {
+			reflectKey = iterable.mapKey(keyField.Type, 0, key)
+		}
+		keyValue.FieldByIndex(keyField.Index).Set(reflectKey)
+
+		keyValue.FieldByIndex(valueField.Index).Set(iterable.groupValue(valueField.Type, value))
+		s.Set(reflect.Append(s, keyValue))
+	}
+
+	res.Elem().Set(s)
+
+	/*
+		// Value
+		result := make([]linq.KeyValue[K, V], 0)
+		linq.From([]T{...}).
+			GroupThis("ItemField").
+			By("KeyField").
+			AndAssignToOrderedSlice(&result)
+
+		// List
+		result := make([]linq.KeyValue[K, []V], 0)
+		linq.From([]T{...}).
+			GroupListsOf("ItemField").
+			By("KeyField").
+			AndAssignToOrderedSlice(&result)
 	*/
 }
 
@@ -156,6 +156,97 @@ func TestAndAssignToMap(t *testing.T) {
 
 ////////////////////////////////////////////////////////////////////////////////
 
+func TestAndAssignToOrderedSlice(t *testing.T) {
+
+	testItems := []testStruct{
+		{Id: 3, Name: "Test 3a"},
+		{Id: 1, Name: "Test 1"},
+		{Id: 3, Name: "Test 3b"},
+	}
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("value", func(t *testing.T) {
+
+		result := make([]KeyValue[int, string], 0)
+		From(testItems).Group("Name").By("Id").AndAssignToOrderedSlice(&result)
+
+		if len(result) != 2 {
+			t.Fatalf("Expected 2 items but got %v", len(result))
+		}
+
+		if result[0] != (KeyValue[int, string]{Key: 3, Value: "Test 3b"}) {
+			t.Errorf("Expected first key but got %v", result[0])
+		}
+
+		if result[1] != (KeyValue[int, string]{Key: 1, Value: "Test 1"}) {
+			t.Errorf("Expected second key but got %v", result[1])
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("list", func(t *testing.T) {
+
+		result := make([]KeyValue[int, []string], 0)
+		From(testItems).GroupListsOf("Name").By("Id").AndAssignToOrderedSlice(&result)
+
+		if len(result) != 2 || result[0].Key != 3 || result[1].Key != 1 {
+			t.Fatalf("Expected keys 3 and 1 but got %v", result)
+		}
+
+		if len(result[0].Value) != 2 || result[0].Value[0] != "Test 3a" || result[0].Value[1] != "Test 3b" {
+			t.Errorf("Expected [Test 3a Test 3b] but got %v", result[0].Value)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("aggregate", func(t *testing.T) {
+
+		result := make([]KeyValue[int, int], 0)
+		From(testItems).GroupBy("Id").Aggregate(Count()).AndAssignToOrderedSlice(&result)
+
+		if len(result) != 2 || result[0] != (KeyValue[int, int]{Key: 3, Value: 2}) {
+			t.Errorf("Expected [{3 2} {1 1}] but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("bad result type", func(t *testing.T) {
+
+		result := make([]string, 0)
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		From(testItems).Group("Name").By("Id").AndAssignToOrderedSlice(&result)
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("bad type", func(t *testing.T) {
+
+		result := make([]KeyValue[string, string], 0)
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		From(testItems).Group("Name").By("Id").AndAssignToOrderedSlice(&result)
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
 func TestAndAssignToSlice(t *testing.T) {
 
 	//----------------------------------------------------------------------------//
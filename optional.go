@@ -0,0 +1,91 @@
+package weaklinq
+
+import "fmt"
+
+//----------------------------------------------------------------------------//
+// Optional                                                                   //
+//----------------------------------------------------------------------------//
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Optional is a wrapper around a value that may or may not be present. It is
+// used where a missing item has to be told apart from a zero-valued item, such
+// as the unmatched side of an outer join.
+type Optional[T any] struct {
+	value    T
+	hasValue bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Some returns an Optional holding the given value.
+func Some[T any](value T) Optional[T] {
+
+	return Optional[T]{
+		value:    value,
+		hasValue: true,
+	}
+
+	/*
+		linq.Some(value)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// None returns an empty Optional.
+func None[T any]() Optional[T] {
+
+	return Optional[T]{}
+
+	/*
+		linq.None[T]()
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// HasValue returns whether the Optional holds a value.
+func (optional Optional[T]) HasValue() bool {
+
+	return optional.hasValue
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Value returns the value held by the Optional. If the Optional is empty,
+// this function will panic.
+func (optional Optional[T]) Value() T {
+
+	if !optional.hasValue {
+		panic(fmt.Sprintf("optional %T has no value", optional))
+	}
+
+	return optional.value
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// OrElse returns the value held by the Optional, or fallback if the Optional
+// is empty.
+func (optional Optional[T]) OrElse(fallback T) T {
+
+	if !optional.hasValue {
+		return fallback
+	}
+
+	return optional.value
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// String returns the held value formatted with %v, or "None" if the Optional
+// is empty.
+func (optional Optional[T]) String() string {
+
+	if !optional.hasValue {
+		return "None"
+	}
+
+	return fmt.Sprintf("Some(%v)", optional.value)
+}
@@ -0,0 +1,73 @@
+package weaklinq
+
+import "testing"
+
+//----------------------------------------------------------------------------//
+// Optional                                                                   //
+//----------------------------------------------------------------------------//
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestSome(t *testing.T) {
+
+	result := Some(0)
+
+	if !result.HasValue() {
+		t.Errorf("Expected value but got none")
+	}
+
+	if result.Value() != 0 {
+		t.Errorf("Expected 0 but got %v", result.Value())
+	}
+
+	if result.OrElse(5) != 0 {
+		t.Errorf("Expected 0 but got %v", result.OrElse(5))
+	}
+
+	if result.String() != "Some(0)" {
+		t.Errorf("Expected Some(0) but got %v", result.String())
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestNone(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("generic", func(t *testing.T) {
+
+		result := None[int]()
+
+		if result.HasValue() {
+			t.Errorf("Expected no value but got %v", result)
+		}
+
+		if result.OrElse(5) != 5 {
+			t.Errorf("Expected 5 but got %v", result.OrElse(5))
+		}
+
+		if result.String() != "None" {
+			t.Errorf("Expected None but got %v", result.String())
+		}
+
+		if result != (Optional[int]{}) {
+			t.Errorf("Expected zero Optional but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("value panics", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		None[testStruct]().Value()
+	})
+
+	//----------------------------------------------------------------------------//
+}
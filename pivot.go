@@ -0,0 +1,216 @@
+package weaklinq
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+//----------------------------------------------------------------------------//
+// Pivot                                                                      //
+//----------------------------------------------------------------------------//
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Table is a dense crosstab of pivoted values, as assigned by
+// AndAssignToTable. Rows and Columns hold the sorted row and column keys, and
+// Cells holds one row of values per row key, with one value per column key.
+// Cells with no items under them hold the zero value of V.
+type Table[R any, C any, V any] struct {
+	Rows    []R
+	Columns []C
+	Cells   [][]V
+}
+
+/////////////////////////////////////////////////////////////////////////////////
+
+// Unpivoted is one field of an item turned into a row of its own by Unpivot,
+// holding the item, the name of the field, and the value of the field.
+type Unpivoted[T any] struct {
+	Item  T
+	Key   string
+	Value any
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// PivotThis returns a new MapIterable that pivots the items, with the existing
+// key as the row key, the given reducer computing each cell from the items
+// under both keys, and the given column selector as the column key. It can be
+// assigned to a map[R]map[C]V with AndAssignToMap, or to a Table with
+// AndAssignToTable.
+func (iterable MapIterable[T]) PivotThis(cell Reducer, columnSelector func(T) any) MapIterable[T] {
+
+	return iterable.ThenByThis(columnSelector).Aggregate(cell)
+
+	/*
+		result := make(map[R]map[C]V)
+		linq.From([]T{...}).
+			GroupBy("RowField").
+			PivotThis(
+				linq.Sum("ValueField"),
+				func(item T) any {
+					return item.ColumnField
+				},
+			).
+			AndAssignToMap(&result)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Pivot returns a new MapIterable that pivots the items, with the existing key
+// as the row key, the given reducer computing each cell, and the given field
+// names as the column key, as with PivotThis. If T is not a struct, or any
+// fieldName is not found, iterating will panic.
+func (iterable MapIterable[T]) Pivot(cell Reducer, columnFieldNames ...string) MapIterable[T] {
+
+	return iterable.ThenBy(columnFieldNames...).Aggregate(cell)
+
+	/*
+		result := linq.Table[R, C, V]{}
+		linq.From([]T{...}).
+			GroupBy("Region").
+			Pivot(linq.Sum("Revenue"), "Quarter").
+			AndAssignToTable(&result)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// AndAssignToTable assigns the result of iterating over the MapIterable to a
+// Table, with its key as the row key and its ThenBy key as the column key. The
+// row and column keys are sorted, so they must be numbers, strings or time.Time
+// values, or CompositeKeys of them when several field names are given, which
+// are sorted field by field. The cells are built like the values of
+// AndAssignToMap. If the MapIterable does not have exactly two key levels,
+// there is a type mismatch, or the result is not a pointer to a Table, this
+// function will panic.
+func (iterable MapIterable[T]) AndAssignToTable(result any) {
+
+	res := reflect.ValueOf(result)
+	if res.Kind() != reflect.Pointer || res.IsNil() || res.Elem().Kind() != reflect.Struct {
+		panic("'result' must be a pointer to a Table")
+	}
+
+	table := res.Elem()
+	rowsValue := table.FieldByName("Rows")
+	columnsValue := table.FieldByName("Columns")
+	cellsValue := table.FieldByName("Cells")
+	if !rowsValue.IsValid() || !columnsValue.IsValid() || !cellsValue.IsValid() ||
+		rowsValue.Kind() != reflect.Slice || columnsValue.Kind() != reflect.Slice ||
+		cellsValue.Kind() != reflect.Slice || cellsValue.Type().Elem().Kind() != reflect.Slice {
+		panic("'result' must be a pointer to a Table")
+	}
+
+	if len(iterable.thenKeySelectors) != 1 {
+		panic(fmt.Sprintf("a table needs a row and a column key, but there are %d key levels", 1+len(iterable.thenKeySelectors)))
+	}
+
+	rows := make([]any, 0)
+	columns := make([]any, 0)
+	seenColumns := make(map[any]bool)
+	cells := make(map[any]map[any]any)
+	for key, value := range iterable.Groups() {
+		levelKeys := splitGroupKey(key, 2)
+		row, column := levelKeys[0], levelKeys[1]
+
+		if _, exists := cells[row]; !exists {
+			rows = append(rows, row)
+			cells[row] = make(map[any]any)
+		}
+		if !seenColumns[column] {
+			seenColumns[column] = true
+			columns = append(columns, column)
+		}
+		cells[row][column] = value
+	}
+
+	slices.SortFunc(rows, compareValues)
+	slices.SortFunc(columns, compareValues)
+
+	rowType := rowsValue.Type().Elem()
+	columnType := columnsValue.Type().Elem()
+	cellRowType := cellsValue.Type().Elem()
+	cellType := cellRowType.Elem()
+
+	rowsValue.Set(reflect.MakeSlice(rowsValue.Type(), 0, len(rows)))
+	for _, row := range rows {
+		rowsValue.Set(reflect.Append(rowsValue, iterable.mapKey(rowType, 0, row)))
+	}
+
+	columnsValue.Set(reflect.MakeSlice(columnsValue.Type(), 0, len(columns)))
+	for _, column := range columns {
+		columnsValue.Set(reflect.Append(columnsValue, iterable.mapKey(columnType, 1, column)))
+	}
+
+	cellsValue.Set(reflect.MakeSlice(cellsValue.Type(), 0, len(rows)))
+	for _, row := range rows {
+		cellRow := reflect.MakeSlice(cellRowType, len(columns), len(columns))
+		for i, column := range columns {
+			if value, exists := cells[row][column]; exists {
+				cellRow.Index(i).Set(iterable.groupValue(cellType, value))
+			}
+		}
+		cellsValue.Set(reflect.Append(cellsValue, cellRow))
+	}
+
+	/*
+		result := linq.Table[R, C, V]{}
+		linq.From([]T{...}).
+			GroupBy("RowField").
+			Pivot(linq.Sum("ValueField"), "ColumnField").
+			AndAssignToTable(&result)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Unpivot returns a new Iterable that turns each item into one row per given
+// field name, holding the item, the field name and the value of the field, in
+// the order the field names are given. If no field names are given, every
+// exported field is used, in declaration order. If T is not a struct, or any
+// fieldName is not found, this function will panic.
+func Unpivot[T any](iterable Iterable[T], fieldNames ...string) Iterable[Unpivoted[T]] {
+
+	structType := reflect.TypeFor[T]()
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+
+	if structType.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("item is not a struct or pointer to struct: %v", reflect.TypeFor[T]()))
+	}
+
+	if len(fieldNames) == 0 {
+		for _, field := range reflect.VisibleFields(structType) {
+			if field.IsExported() && !field.Anonymous {
+				fieldNames = append(fieldNames, field.Name)
+			}
+		}
+	}
+
+	// Checks the field names up front, rather than when iterating.
+	getFieldTypes[T](fieldNames...)
+
+	nameFuncs := make([]func(T) any, len(fieldNames))
+	for i, fieldName := range fieldNames {
+		nameFuncs[i] = getFieldNameFunc[T](fieldName)
+	}
+
+	return Iterable[Unpivoted[T]]{
+		Seq: func(yield func(Unpivoted[T]) bool) {
+			for item := range iterable.Seq {
+				for i, nameFunc := range nameFuncs {
+					if !yield(Unpivoted[T]{Item: item, Key: fieldNames[i], Value: nameFunc(item)}) {
+						return
+					}
+				}
+			}
+		},
+	}
+
+	/*
+		linq.Unpivot(linq.From([]T{...}), "Q1Field", "Q2Field", "Q3Field", "Q4Field")
+	*/
+}
@@ -0,0 +1,330 @@
+package weaklinq
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+//----------------------------------------------------------------------------//
+// Pivot                                                                      //
+//----------------------------------------------------------------------------//
+
+////////////////////////////////////////////////////////////////////////////////
+
+type testQuarterSale struct {
+	Region  string
+	Quarter int
+	Revenue int
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func testQuarterSales() []testQuarterSale {
+
+	return []testQuarterSale{
+		{Region: "West", Quarter: 2, Revenue: 5},
+		{Region: "East", Quarter: 1, Revenue: 10},
+		{Region: "East", Quarter: 2, Revenue: 20},
+		{Region: "East", Quarter: 1, Revenue: 30},
+		{Region: "North", Quarter: 3, Revenue: 7},
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestPivotThis(t *testing.T) {
+
+	result := make(map[string]map[int]int)
+	From(testQuarterSales()).
+		GroupBy("Region").
+		PivotThis(Sum("Revenue"), func(sale testQuarterSale) any { return sale.Quarter }).
+		AndAssignToMap(&result)
+
+	if result["East"][1] != 40 || result["East"][2] != 20 || result["West"][2] != 5 {
+		t.Errorf("Expected East 40/20 and West 5 but got %v", result)
+	}
+
+	if _, exists := result["West"][1]; exists {
+		t.Errorf("Expected no West Q1 cell but got %v", result["West"][1])
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestPivot(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("map", func(t *testing.T) {
+
+		result := make(map[string]map[int]int)
+		From(testQuarterSales()).
+			GroupBy("Region").
+			Pivot(Count(), "Quarter").
+			AndAssignToMap(&result)
+
+		if result["East"][1] != 2 || result["North"][3] != 1 {
+			t.Errorf("Expected East Q1 2 and North Q3 1 but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("bad field name", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		result := make(map[string]map[int]int)
+		From(testQuarterSales()).GroupBy("Region").Pivot(Count(), "BadFieldName").AndAssignToMap(&result)
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestAndAssignToTable(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("generic", func(t *testing.T) {
+
+		result := Table[string, int, int]{}
+		From(testQuarterSales()).
+			GroupBy("Region").
+			Pivot(Sum("Revenue"), "Quarter").
+			AndAssignToTable(&result)
+
+		if !slices.Equal(result.Rows, []string{"East", "North", "West"}) {
+			t.Errorf("Expected [East North West] but got %v", result.Rows)
+		}
+
+		if !slices.Equal(result.Columns, []int{1, 2, 3}) {
+			t.Errorf("Expected [1 2 3] but got %v", result.Columns)
+		}
+
+		expected := [][]int{
+			{40, 20, 0},
+			{0, 0, 7},
+			{0, 5, 0},
+		}
+		if !slices.EqualFunc(result.Cells, expected, slices.Equal) {
+			t.Errorf("Expected %v but got %v", expected, result.Cells)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("lists", func(t *testing.T) {
+
+		result := Table[string, int, []int]{}
+		From(testQuarterSales()).
+			GroupListsOf("Revenue").
+			By("Region").
+			ThenBy("Quarter").
+			AndAssignToTable(&result)
+
+		if !slices.Equal(result.Cells[0][0], []int{10, 30}) {
+			t.Errorf("Expected [10 30] but got %v", result.Cells[0][0])
+		}
+
+		if result.Cells[2][0] != nil {
+			t.Errorf("Expected nil but got %v", result.Cells[2][0])
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("composite keys", func(t *testing.T) {
+
+		type monthSale struct {
+			Year   int
+			Region string
+			Month  int
+			Name   string
+			Value  int
+		}
+		type rowKey struct {
+			Year   int
+			Region string
+		}
+		type columnKey struct {
+			Month int
+			Name  string
+		}
+
+		sales := []monthSale{
+			{Year: 2024, Region: "West", Month: 2, Name: "B", Value: 1},
+			{Year: 2024, Region: "East", Month: 1, Name: "B", Value: 2},
+			{Year: 2023, Region: "West", Month: 1, Name: "A", Value: 3},
+			{Year: 2024, Region: "East", Month: 1, Name: "B", Value: 4},
+		}
+
+		result := Table[rowKey, columnKey, int]{}
+		From(sales).
+			GroupBy("Year", "Region").
+			Pivot(Sum("Value"), "Month", "Name").
+			AndAssignToTable(&result)
+
+		expectedRows := []rowKey{{2023, "West"}, {2024, "East"}, {2024, "West"}}
+		if !slices.Equal(result.Rows, expectedRows) {
+			t.Errorf("Expected %v but got %v", expectedRows, result.Rows)
+		}
+
+		expectedColumns := []columnKey{{1, "A"}, {1, "B"}, {2, "B"}}
+		if !slices.Equal(result.Columns, expectedColumns) {
+			t.Errorf("Expected %v but got %v", expectedColumns, result.Columns)
+		}
+
+		expected := [][]int{
+			{3, 0, 0},
+			{0, 6, 0},
+			{0, 0, 1},
+		}
+		if !slices.EqualFunc(result.Cells, expected, slices.Equal) {
+			t.Errorf("Expected %v but got %v", expected, result.Cells)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("replaces existing", func(t *testing.T) {
+
+		result := Table[string, int, int]{Rows: []string{"Old"}}
+		From(testQuarterSales()).
+			GroupBy("Region").
+			Pivot(Count(), "Quarter").
+			AndAssignToTable(&result)
+
+		if len(result.Rows) != 3 || len(result.Cells) != 3 {
+			t.Errorf("Expected 3 rows but got %v", result.Rows)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("one key level", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		result := Table[string, int, int]{}
+		From(testQuarterSales()).GroupBy("Region").Aggregate(Count()).AndAssignToTable(&result)
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("bad result type", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		result := make(map[string]map[int]int)
+		From(testQuarterSales()).GroupBy("Region").Pivot(Count(), "Quarter").AndAssignToTable(&result)
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestUnpivot(t *testing.T) {
+
+	type wide struct {
+		Region string
+		Q1     int
+		Q2     int
+		note   string
+	}
+
+	items := []wide{
+		{Region: "East", Q1: 10, Q2: 20},
+		{Region: "West", Q1: 5, Q2: 0},
+	}
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("generic", func(t *testing.T) {
+
+		result := make([]string, 0)
+		for row := range Unpivot(From(items), "Q1", "Q2").Seq {
+			result = append(result, fmt.Sprintf("%s/%s/%v", row.Item.Region, row.Key, row.Value))
+		}
+
+		expected := []string{"East/Q1/10", "East/Q2/20", "West/Q1/5", "West/Q2/0"}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("all fields", func(t *testing.T) {
+
+		keys := make([]string, 0)
+		for row := range Unpivot(From(items[:1])).Seq {
+			keys = append(keys, row.Key)
+		}
+
+		if !slices.Equal(keys, []string{"Region", "Q1", "Q2"}) {
+			t.Errorf("Expected [Region Q1 Q2] but got %v", keys)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("round trip", func(t *testing.T) {
+
+		result := make(map[string]map[string]int)
+		Unpivot(From(items), "Q1", "Q2").
+			GroupByThis(func(row Unpivoted[wide]) any { return row.Item.Region }).
+			PivotThis(
+				SumThis(func(row Unpivoted[wide]) any { return row.Value }),
+				func(row Unpivoted[wide]) any { return row.Key },
+			).
+			AndAssignToMap(&result)
+
+		if result["East"]["Q2"] != 20 || result["West"]["Q1"] != 5 {
+			t.Errorf("Expected East Q2 20 and West Q1 5 but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("bad field name", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		Unpivot(From(items), "Q3")
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("not a struct", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		Unpivot(From([]int{1}))
+	})
+
+	//----------------------------------------------------------------------------//
+}
@@ -62,3 +62,78 @@ func (iterable Iterable[T]) AsAny() Iterable[any] {
 		)
 	*/
 }
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Select returns a new Iterable where the items are transformed by selector.
+// Unlike GetThese, the element type of the result is preserved, so the chain
+// stays typed without any type assertions downstream.
+func Select[T any, U any](iterable Iterable[T], selector func(T) U) Iterable[U] {
+
+	return Iterable[U]{
+		Seq: func(yield func(U) bool) {
+			iterable.Seq(func(item T) bool {
+				return yield(selector(item))
+			})
+		},
+	}
+
+	/*
+		linq.Select(
+			linq.From([]T{...}),
+			func(item T) U {
+				return item.ItemField
+			},
+		)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// SelectIndexed returns a new Iterable where the items are transformed by
+// selector, which also receives the zero-based index of each item.
+func SelectIndexed[T any, U any](iterable Iterable[T], selector func(int, T) U) Iterable[U] {
+
+	return Iterable[U]{
+		Seq: func(yield func(U) bool) {
+			index := 0
+			iterable.Seq(func(item T) bool {
+				result := selector(index, item)
+				index++
+				return yield(result)
+			})
+		},
+	}
+
+	/*
+		linq.SelectIndexed(
+			linq.From([]T{...}),
+			func(index int, item T) U {
+				return ...
+			},
+		)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// GetAs returns a new Iterable where the items are transformed by fieldName
+// into values of type U. The field is validated against T once, so if T is
+// not a struct or pointer to struct, fieldName is not found or not exported,
+// or the field is not assignable to U, this function will panic before
+// anything is iterated.
+// A nil item yields the zero value of U.
+func GetAs[T any, U any](iterable Iterable[T], fieldName string) Iterable[U] {
+
+	return Select(
+		iterable,
+		getTypedFieldNameFunc[T, U](fieldName),
+	)
+
+	/*
+		linq.GetAs[T, U](
+			linq.From([]T{...}),
+			"ItemField",
+		)
+	*/
+}
@@ -1,7 +1,9 @@
 package weaklinq
 
 import (
+	"fmt"
 	"iter"
+	"slices"
 	"testing"
 )
 
@@ -154,3 +156,224 @@ func TestAsAny(t *testing.T) {
 		t.Errorf("Expected no item but got %v", item)
 	}
 }
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestSelect(t *testing.T) {
+
+	testItems := []testStruct{
+		{Id: 1, Name: "Test 1"},
+		{Id: 2, Name: "Test 2"},
+	}
+
+	result := Select(
+		From(testItems),
+		func(t testStruct) int { return t.Id * 10 },
+	)
+	resultIterator, _ := iter.Pull(result.Seq)
+
+	if resultIterator == nil {
+		t.Errorf("Expected iterator but got nil")
+	}
+
+	if item, ok := resultIterator(); !ok || item != 10 {
+		t.Errorf("Expected 10 but got %v", item)
+	}
+
+	if item, ok := resultIterator(); !ok || item != 20 {
+		t.Errorf("Expected 20 but got %v", item)
+	}
+
+	if item, ok := resultIterator(); ok {
+		t.Errorf("Expected no item but got %v", item)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestSelectIndexed(t *testing.T) {
+
+	testItems := []string{"a", "b", "c"}
+
+	result := make([]string, 0)
+	for item := range SelectIndexed(
+		From(testItems),
+		func(index int, item string) string { return fmt.Sprintf("%d%s", index, item) },
+	).Seq {
+		result = append(result, item)
+	}
+
+	expected := []string{"0a", "1b", "2c"}
+	if !slices.Equal(result, expected) {
+		t.Errorf("Expected %v but got %v", expected, result)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestGetAs(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("generic", func(t *testing.T) {
+
+		testItems := []testStruct{
+			{Id: 1, Name: "Test 1"},
+			{Id: 2, Name: "Test 2"},
+		}
+
+		result := make([]string, 0)
+		for name := range GetAs[testStruct, string](From(testItems), "Name").Seq {
+			result = append(result, name)
+		}
+
+		expected := []string{"Test 1", "Test 2"}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("struct pointer", func(t *testing.T) {
+
+		testItems := []*testStruct{
+			{Id: 1, Name: "Test 1"},
+			{Id: 2, Name: "Test 2"},
+		}
+
+		result := make([]int, 0)
+		for id := range GetAs[*testStruct, int](From(testItems), "Id").Seq {
+			result = append(result, id)
+		}
+
+		expected := []int{1, 2}
+		if !slices.Equal(result, expected) {
+			t.Errorf("Expected %v but got %v", expected, result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("interface result", func(t *testing.T) {
+
+		testItems := []testStruct{
+			{Id: 1, Name: "Test 1"},
+		}
+
+		result := make([]any, 0)
+		for id := range GetAs[testStruct, any](From(testItems), "Id").Seq {
+			result = append(result, id)
+		}
+
+		if len(result) != 1 || result[0] != 1 {
+			t.Errorf("Expected [1] but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("nil interface field", func(t *testing.T) {
+
+		type withErr struct {
+			Err error
+		}
+
+		testItems := []withErr{{}, {Err: fmt.Errorf("failed")}}
+
+		anyResult := slices.Collect(GetAs[withErr, any](From(testItems), "Err").Seq)
+		if len(anyResult) != 2 || anyResult[0] != nil || anyResult[1] == nil {
+			t.Errorf("Expected [<nil> failed] but got %v", anyResult)
+		}
+
+		errResult := slices.Collect(GetAs[withErr, error](From(testItems), "Err").Seq)
+		if len(errResult) != 2 || errResult[0] != nil || errResult[1].Error() != "failed" {
+			t.Errorf("Expected [<nil> failed] but got %v", errResult)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("nil item", func(t *testing.T) {
+
+		testItems := []*testStruct{nil, {Id: 2, Name: "Test 2"}}
+
+		result := slices.Collect(GetAs[*testStruct, int](From(testItems), "Id").Seq)
+		if !slices.Equal(result, []int{0, 2}) {
+			t.Errorf("Expected [0 2] but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("bad field name", func(t *testing.T) {
+
+		testItems := []testStruct{
+			{Id: 1, Name: "Test 1"},
+		}
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		GetAs[testStruct, string](From(testItems), "BadFieldName")
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("bad field type", func(t *testing.T) {
+
+		testItems := []testStruct{
+			{Id: 1, Name: "Test 1"},
+		}
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		GetAs[testStruct, string](From(testItems), "Id")
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("unexported field", func(t *testing.T) {
+
+		type secret struct {
+			Id   int
+			name string
+		}
+
+		testItems := []secret{
+			{Id: 1, name: "Test 1"},
+		}
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		GetAs[secret, string](From(testItems), "name")
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("bad item type", func(t *testing.T) {
+
+		testItems := []int{1, 2, 3}
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		GetAs[int, string](From(testItems), "Name")
+	})
+
+	//----------------------------------------------------------------------------//
+}
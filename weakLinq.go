@@ -1,10 +1,13 @@
 package weaklinq
 
 import (
+	"cmp"
 	"fmt"
 	"iter"
+	"math"
 	"reflect"
 	"slices"
+	"time"
 )
 
 //----------------------------------------------------------------------------//
@@ -15,7 +18,9 @@ import (
 
 // Iterable is the base structure for an iterable. It allows for the lazy
 // iteration of a collection of items and exists to allow functions to be
-// called on the collection.
+// called on the collection. Every operator keeps its state per iteration, so
+// an Iterable can be iterated more than once, and by several goroutines at
+// once, as long as its source can be.
 type Iterable[T any] struct {
 	iter.Seq[T]
 }
@@ -61,6 +66,319 @@ func getFieldNameFunc[T any](fieldName string) func(T) any {
 	}
 }
 
+////////////////////////////////////////////////////////////////////////////////
+
+// getTypedFieldNameFunc returns a function that returns the value of the given
+// field name as a U. Unlike getFieldNameFunc, the field is looked up once
+// against the static type of T, so if T is not a struct or pointer to struct,
+// fieldName is not found or not exported, or the field is not assignable to U,
+// this function will panic immediately rather than when the first item is
+// read. A nil item,
+// or a field reached through a nil embedded pointer, is read as the zero U.
+func getTypedFieldNameFunc[T any, U any](fieldName string) func(T) U {
+
+	itemType := reflect.TypeFor[T]()
+	structType := itemType
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+
+	if structType.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("item is not a struct or pointer to struct: %v", itemType))
+	}
+
+	field, ok := structType.FieldByName(fieldName)
+	if !ok {
+		panic(fmt.Sprintf("field name '%s' not found in struct %v", fieldName, itemType))
+	}
+
+	if !field.IsExported() {
+		panic(fmt.Sprintf("field '%s' in struct %v is not exported", fieldName, itemType))
+	}
+
+	resultType := reflect.TypeFor[U]()
+	if !field.Type.AssignableTo(resultType) {
+		panic(fmt.Sprintf("field '%s' in struct %v is %v, not %v", fieldName, itemType, field.Type, resultType))
+	}
+
+	return func(item T) U {
+
+		var result U
+
+		res := reflect.Indirect(reflect.ValueOf(item))
+		if !res.IsValid() {
+			return result
+		}
+
+		fieldValue, err := res.FieldByIndexErr(field.Index)
+		if err != nil {
+			return result
+		}
+
+		// Set rather than a type assertion, so nil interface fields are kept.
+		reflect.ValueOf(&result).Elem().Set(fieldValue)
+		return result
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// getFieldNamesFunc returns a function that returns the value of the given
+// field name, or a CompositeKey of the values of the given field names if
+// there is more than one. If T is not a struct or pointer to struct, or any
+// fieldName is not found, the returned function will panic.
+func getFieldNamesFunc[T any](fieldNames ...string) func(T) any {
+
+	if len(fieldNames) == 0 {
+		panic("at least one field name must be given")
+	}
+
+	if len(fieldNames) == 1 {
+		return getFieldNameFunc[T](fieldNames[0])
+	}
+
+	nameFuncs := make([]func(T) any, len(fieldNames))
+	for i, fieldName := range fieldNames {
+		nameFuncs[i] = getFieldNameFunc[T](fieldName)
+	}
+
+	return func(item T) any {
+
+		values := make([]any, len(nameFuncs))
+		for i, nameFunc := range nameFuncs {
+			values[i] = nameFunc(item)
+		}
+
+		return CompositeKey(values...)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// getFieldTypes returns the types of the given field names in T, or nil if T
+// is not a struct or pointer to struct. If any fieldName is not found in a
+// struct, this function will panic.
+func getFieldTypes[T any](fieldNames ...string) []reflect.Type {
+
+	structType := reflect.TypeFor[T]()
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+
+	if structType.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fieldTypes := make([]reflect.Type, len(fieldNames))
+	for i, fieldName := range fieldNames {
+		field, ok := structType.FieldByName(fieldName)
+		if !ok {
+			panic(fmt.Sprintf("field name '%s' not found in struct %v", fieldName, structType))
+		}
+		fieldTypes[i] = field.Type
+	}
+
+	return fieldTypes
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// CompositeKey returns a comparable key made up of the given values, for use
+// as a join or grouping key built from more than one field. Two composite keys
+// are equal if they have the same number of values and each pair of values is
+// equal. Each value must itself be comparable.
+func CompositeKey(values ...any) any {
+
+	key := reflect.New(reflect.ArrayOf(len(values), reflect.TypeFor[any]())).Elem()
+	for i := range values {
+		key.Index(i).Set(reflect.ValueOf(&values[i]).Elem())
+	}
+
+	return key.Interface()
+
+	/*
+		linq.From([]T{...}).
+			Join(linq.From([]TRight{...}).AsAny()).
+			OnThis(
+				func(left T) any {
+					return linq.CompositeKey(left.KeyField1, left.KeyField2)
+				},
+			)
+	*/
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// compareValues compares two values of the same ordered type, returning -1 if
+// a is less than b, 0 if they are equal, and 1 if a is greater than b.
+// Integers, unsigned integers, floats, strings and time.Time values are
+// supported, as are CompositeKeys of them, which are compared element by
+// element. If the values are of different types, or of an unsupported type,
+// this function will panic.
+func compareValues(a any, b any) int {
+
+	if aTime, ok := a.(time.Time); ok {
+		bTime, ok := b.(time.Time)
+		if !ok {
+			panic(fmt.Sprintf("cannot compare %T to %T", a, b))
+		}
+		return aTime.Compare(bTime)
+	}
+
+	aValue := reflect.ValueOf(a)
+	bValue := reflect.ValueOf(b)
+	if !aValue.IsValid() || !bValue.IsValid() || aValue.Type() != bValue.Type() {
+		panic(fmt.Sprintf("cannot compare %T to %T", a, b))
+	}
+
+	switch aValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(aValue.Int(), bValue.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(aValue.Uint(), bValue.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(aValue.Float(), bValue.Float())
+	case reflect.String:
+		return cmp.Compare(aValue.String(), bValue.String())
+	case reflect.Array:
+		if aValue.Type().Elem().Kind() != reflect.Interface {
+			break
+		}
+		for i := range aValue.Len() {
+			if result := compareValues(aValue.Index(i).Interface(), bValue.Index(i).Interface()); result != 0 {
+				return result
+			}
+		}
+		return 0
+	}
+
+	panic(fmt.Sprintf("values of type %T are not ordered", a))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// offsetValue returns value moved up by distance if sign is positive, or down
+// by distance if sign is negative, for computing join key bounds. Numeric
+// values are offset by a numeric distance, and time.Time values by a
+// time.Duration. Integer and unsigned values can only be offset by a whole
+// distance, so a float distance with a fractional part is not truncated.
+// Integer and unsigned values stop at the smallest or largest value of their
+// type rather than wrapping, even if the distance itself does not fit in
+// their type. If the distance is negative, or cannot be applied to the value,
+// this function will panic.
+func offsetValue(value any, distance any, sign int) any {
+
+	if valueTime, ok := value.(time.Time); ok {
+		duration, ok := distance.(time.Duration)
+		if !ok {
+			panic(fmt.Sprintf("cannot offset %T by %T", value, distance))
+		}
+		if duration < 0 {
+			panic(fmt.Sprintf("cannot offset %T by a negative %v", value, distance))
+		}
+		return valueTime.Add(time.Duration(sign) * duration)
+	}
+
+	valueValue := reflect.ValueOf(value)
+	distanceValue := reflect.ValueOf(distance)
+	if !valueValue.IsValid() || !distanceValue.IsValid() || !distanceValue.CanConvert(valueValue.Type()) {
+		panic(fmt.Sprintf("cannot offset %T by %T", value, distance))
+	}
+
+	if (distanceValue.CanInt() && distanceValue.Int() < 0) || (distanceValue.CanFloat() && distanceValue.Float() < 0) {
+		panic(fmt.Sprintf("cannot offset %T by a negative %v", value, distance))
+	}
+
+	result := reflect.New(valueValue.Type()).Elem()
+
+	switch valueValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bits := valueValue.Type().Bits()
+		minValue, maxValue := int64(-1)<<(bits-1), int64(1)<<(bits-1)-1
+		current := valueValue.Int()
+		magnitude := wholeDistance(value, distanceValue)
+		// The room to each limit always fits in a uint64, and the subtraction
+		// gives it exactly even where the int64 difference would overflow.
+		switch {
+		case sign > 0 && magnitude >= uint64(maxValue)-uint64(current):
+			result.SetInt(maxValue)
+		case sign > 0:
+			result.SetInt(int64(uint64(current) + magnitude))
+		case magnitude >= uint64(current)-uint64(minValue):
+			result.SetInt(minValue)
+		default:
+			result.SetInt(int64(uint64(current) - magnitude))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		maxValue := uint64(math.MaxUint64) >> (64 - valueValue.Type().Bits())
+		current := valueValue.Uint()
+		magnitude := wholeDistance(value, distanceValue)
+		switch {
+		case sign > 0 && magnitude >= maxValue-current:
+			result.SetUint(maxValue)
+		case sign > 0:
+			result.SetUint(current + magnitude)
+		case magnitude >= current:
+			result.SetUint(0)
+		default:
+			result.SetUint(current - magnitude)
+		}
+	case reflect.Float32, reflect.Float64:
+		result.SetFloat(valueValue.Float() + float64(sign)*distanceValue.Convert(valueValue.Type()).Float())
+	default:
+		panic(fmt.Sprintf("cannot offset %T by %T", value, distance))
+	}
+
+	return result.Interface()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// wholeDistance returns the given non-negative distance as a uint64, before
+// any conversion to the type of value, so that a distance too large for that
+// type is not wrapped. Float distances beyond the range of a uint64 are
+// returned as its largest value. If the distance is a float with a fractional
+// part, or is not a number, this function will panic.
+func wholeDistance(value any, distanceValue reflect.Value) uint64 {
+
+	switch {
+	case distanceValue.CanInt():
+		return uint64(distanceValue.Int())
+	case distanceValue.CanUint():
+		return distanceValue.Uint()
+	case distanceValue.CanFloat():
+		distance := distanceValue.Float()
+		if distance != math.Trunc(distance) {
+			panic(fmt.Sprintf("cannot offset %T by a fractional %v", value, distanceValue.Interface()))
+		}
+		if distance >= math.MaxUint64 {
+			return math.MaxUint64
+		}
+		return uint64(distance)
+	}
+
+	panic(fmt.Sprintf("cannot offset %T by %T", value, distanceValue.Interface()))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// floatValue returns the given integer, unsigned integer or float value as a
+// float64. If the value is of any other type, this function will panic.
+func floatValue(value any) float64 {
+
+	valueValue := reflect.ValueOf(value)
+	switch valueValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(valueValue.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(valueValue.Uint())
+	case reflect.Float32, reflect.Float64:
+		return valueValue.Float()
+	}
+
+	panic(fmt.Sprintf("values of type %T are not numeric", value))
+}
+
 //----------------------------------------------------------------------------//
 // Constructors                                                               //
 //----------------------------------------------------------------------------//
@@ -1,8 +1,13 @@
 package weaklinq
 
 import (
+	"fmt"
 	"iter"
+	"math"
+	"slices"
+	"sync"
 	"testing"
+	"time"
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -13,6 +18,260 @@ type testStruct struct {
 	IsActive bool
 }
 
+//----------------------------------------------------------------------------//
+// Common                                                                     //
+//----------------------------------------------------------------------------//
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestCompositeKey(t *testing.T) {
+
+	if CompositeKey(1, "a") != CompositeKey(1, "a") {
+		t.Errorf("Expected equal keys but got %v and %v", CompositeKey(1, "a"), CompositeKey(1, "a"))
+	}
+
+	if CompositeKey(1, "a") == CompositeKey(1, "b") {
+		t.Errorf("Expected different keys but got %v", CompositeKey(1, "a"))
+	}
+
+	if CompositeKey(1, "a") == CompositeKey(1, "a", nil) {
+		t.Errorf("Expected keys of different lengths to differ")
+	}
+
+	counts := make(map[any]int)
+	counts[CompositeKey(1, nil)]++
+	counts[CompositeKey(1, nil)]++
+	counts[CompositeKey(2, nil)]++
+
+	if len(counts) != 2 || counts[CompositeKey(1, nil)] != 2 {
+		t.Errorf("Expected 2 distinct map keys but got %v", counts)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestCompareValues(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("ordered types", func(t *testing.T) {
+
+		now := time.Now()
+
+		cases := []struct {
+			a        any
+			b        any
+			expected int
+		}{
+			{a: 1, b: 2, expected: -1},
+			{a: int8(3), b: int8(3), expected: 0},
+			{a: uint(5), b: uint(4), expected: 1},
+			{a: 1.5, b: 0.5, expected: 1},
+			{a: "a", b: "b", expected: -1},
+			{a: now, b: now.Add(time.Second), expected: -1},
+			{a: CompositeKey(1, "b"), b: CompositeKey(1, "a"), expected: 1},
+			{a: CompositeKey(1, "b"), b: CompositeKey(2, "a"), expected: -1},
+			{a: CompositeKey(1, "a"), b: CompositeKey(1, "a"), expected: 0},
+		}
+
+		for _, c := range cases {
+			if result := compareValues(c.a, c.b); result != c.expected {
+				t.Errorf("Expected %v comparing %v to %v but got %v", c.expected, c.a, c.b, result)
+			}
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("mismatched types", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		compareValues(1, "1")
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("unordered type", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		compareValues(testStruct{}, testStruct{})
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestOffsetValue(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("numeric", func(t *testing.T) {
+
+		if result := offsetValue(10, 3, -1); result != 7 {
+			t.Errorf("Expected 7 but got %v", result)
+		}
+
+		if result := offsetValue(int64(10), 3, 1); result != int64(13) {
+			t.Errorf("Expected 13 but got %v", result)
+		}
+
+		if result := offsetValue(uint(2), 3, -1); result != uint(0) {
+			t.Errorf("Expected 0 but got %v", result)
+		}
+
+		if result := offsetValue(1.5, 0.25, 1); result != 1.75 {
+			t.Errorf("Expected 1.75 but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("time", func(t *testing.T) {
+
+		now := time.Now()
+
+		if result := offsetValue(now, time.Minute, -1); result != now.Add(-time.Minute) {
+			t.Errorf("Expected %v but got %v", now.Add(-time.Minute), result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("bad distance", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		offsetValue(time.Now(), 5, 1)
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("saturates", func(t *testing.T) {
+
+		if result := offsetValue(int8(120), 10, 1); result != int8(math.MaxInt8) {
+			t.Errorf("Expected %v but got %v", math.MaxInt8, result)
+		}
+
+		if result := offsetValue(int8(-120), 10, -1); result != int8(math.MinInt8) {
+			t.Errorf("Expected %v but got %v", math.MinInt8, result)
+		}
+
+		if result := offsetValue(uint8(250), 10, 1); result != uint8(math.MaxUint8) {
+			t.Errorf("Expected %v but got %v", math.MaxUint8, result)
+		}
+
+		if result := offsetValue(int64(math.MaxInt64-1), 10, 1); result != int64(math.MaxInt64) {
+			t.Errorf("Expected %v but got %v", int64(math.MaxInt64), result)
+		}
+
+		if result := offsetValue(uint64(math.MaxUint64-1), 10, 1); result != uint64(math.MaxUint64) {
+			t.Errorf("Expected %v but got %v", uint64(math.MaxUint64), result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("distance beyond type", func(t *testing.T) {
+
+		if result := offsetValue(int8(0), 300, 1); result != int8(math.MaxInt8) {
+			t.Errorf("Expected %v but got %v", math.MaxInt8, result)
+		}
+
+		if result := offsetValue(int8(0), 300, -1); result != int8(math.MinInt8) {
+			t.Errorf("Expected %v but got %v", math.MinInt8, result)
+		}
+
+		if result := offsetValue(uint8(10), 1000.0, 1); result != uint8(math.MaxUint8) {
+			t.Errorf("Expected %v but got %v", math.MaxUint8, result)
+		}
+
+		if result := offsetValue(int64(math.MinInt64), uint64(math.MaxUint64), 1); result != int64(math.MaxInt64) {
+			t.Errorf("Expected %v but got %v", int64(math.MaxInt64), result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("negative distance", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		offsetValue(uint8(10), -1, 1)
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("whole float distance", func(t *testing.T) {
+
+		if result := offsetValue(10, 2.0, 1); result != 12 {
+			t.Errorf("Expected 12 but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("fractional distance", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		offsetValue(10, 1.9, 1)
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestFloatValue(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("numbers", func(t *testing.T) {
+
+		if result := floatValue(3) + floatValue(uint8(2)) + floatValue(float32(0.5)); result != 5.5 {
+			t.Errorf("Expected 5.5 but got %v", result)
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("not numeric", func(t *testing.T) {
+
+		defer func() {
+			if err := recover(); err == nil {
+				t.Errorf("Expected panic but got %v", err)
+			}
+		}()
+
+		floatValue("3")
+	})
+
+	//----------------------------------------------------------------------------//
+}
+
 //----------------------------------------------------------------------------//
 // Constructors                                                               //
 //----------------------------------------------------------------------------//
@@ -68,3 +327,144 @@ func TestFrom(t *testing.T) {
 
 	//----------------------------------------------------------------------------//
 }
+
+//----------------------------------------------------------------------------//
+// Reiteration                                                                //
+//----------------------------------------------------------------------------//
+
+////////////////////////////////////////////////////////////////////////////////
+
+// testSeq is an iteration of a query that can be run more than once, returning
+// what it yielded as a string for comparison.
+type testSeq func() string
+
+////////////////////////////////////////////////////////////////////////////////
+
+func testSeqOf[T any](iterable Iterable[T]) testSeq {
+
+	return func() string {
+		return fmt.Sprint(slices.Collect(iterable.Seq))
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func testSeq2Of[K any, V any](seq iter.Seq2[K, V]) testSeq {
+
+	return func() string {
+		result := make([]string, 0)
+		for key, value := range seq {
+			result = append(result, fmt.Sprint(key, ":", value))
+		}
+		return fmt.Sprint(result)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// testReiterableQueries returns a query built with each lazy operator. Each
+// query is built once, so iterating it again reuses the same operator state.
+func testReiterableQueries() map[string]testSeq {
+
+	type tagged struct {
+		Id   int
+		Tags []string
+	}
+
+	items := []testStruct{
+		{Id: 1, Name: "A", IsActive: true},
+		{Id: 2, Name: "B", IsActive: false},
+		{Id: 1, Name: "A", IsActive: true},
+		{Id: 3, Name: "C", IsActive: true},
+	}
+	source := From(items)
+	right := From([]testStruct{{Id: 1, Name: "X"}, {Id: 4, Name: "Y"}, {Id: 2, Name: "Z"}})
+	ids := func(item testStruct) int { return item.Id }
+
+	return map[string]testSeq{
+		"FilterOnThis":  testSeqOf(source.FilterOnThis(func(item testStruct) bool { return item.Id > 1 })),
+		"FilterOn":      testSeqOf(source.FilterOn("IsActive")),
+		"Distinct":      testSeqOf(source.Distinct()),
+		"GetThese":      testSeqOf(source.GetThese(func(item testStruct) any { return item.Name })),
+		"Get":           testSeqOf(source.Get("Name")),
+		"AsAny":         testSeqOf(source.AsAny()),
+		"Select":        testSeqOf(Select(source, ids)),
+		"SelectIndexed": testSeqOf(SelectIndexed(source, func(index int, item testStruct) int { return index * item.Id })),
+		"GetAs":         testSeqOf(GetAs[testStruct, string](source, "Name")),
+		"FlattenThese": testSeqOf(source.FlattenThese(func(item testStruct) Iterable[any] {
+			return From([]any{item.Id, item.Name})
+		})),
+		"Flatten": testSeqOf(From([]tagged{{Id: 1, Tags: []string{"a", "b"}}}).Flatten("Tags")),
+		"Join": testSeqOf(source.Join(right.AsAny()).On("Id").Equals("Id").AsThis(
+			func(left testStruct, right any) any { return left.Name + right.(testStruct).Name },
+		)),
+		"FullOuterJoin":  testSeqOf(source.FullOuterJoin(right.AsAny()).On("Id").Equals("Id").AsOptionalPairs()),
+		"GroupJoin":      testSeqOf(source.GroupJoin(right.AsAny()).On("Id").Equals("Id").AsGroups()),
+		"SemiJoin":       testSeqOf(source.SemiJoin(right.AsAny()).On("Id").Equals("Id").AsLeftItems()),
+		"AssumingSorted": testSeqOf(From([]int{1, 2, 2, 3}).FullOuterJoinSlice([]int{2, 3, 4}).AssumingSorted().AsOptionalPairs()),
+		"JoinWhere": testSeqOf(source.Join(right.AsAny()).JoinWhere(
+			func(left testStruct, right any) bool { return left.Id < right.(testStruct).Id },
+		).AsPairs()),
+		"Between":         testSeqOf(From([]int{1, 5}).JoinSlice([]int{2, 3, 6}).BetweenThis(func(item int) any { return item }, func(item int) any { return item + 2 }).AsPairs()),
+		"Within":          testSeqOf(From([]int{1, 5}).JoinSlice([]int{2, 3, 6}).Within(1).AsPairs()),
+		"AsOfJoin":        testSeqOf(From([]int{1, 5}).AsOfJoinSlice([]int{0, 3, 4}).AsPairs()),
+		"CrossJoin":       testSeqOf(From([]int{1, 2}).CrossJoinSlice([]int{3, 4}).AsPairs()),
+		"KeyComparer":     testSeqOf(source.Join(right.AsAny()).On("Id").Equals("Id").WithKeyComparer(func(key any) uint64 { return uint64(key.(int)) }, func(a any, b any) bool { return a == b }).AsPairs()),
+		"JoinOn":          testSeqOf(JoinOn(source, right, ids, ids)),
+		"FullOuterJoinOn": testSeqOf(FullOuterJoinOn(source, right, ids, ids)),
+		"GroupJoinOn":     testSeqOf(GroupJoinOn(source, right, ids, ids)),
+		"AntiJoinOn":      testSeqOf(AntiJoinOn(source, right, ids, ids)),
+		"CrossJoinOf":     testSeqOf(CrossJoinOf(source, right)),
+		"Product":         testSeqOf(Product(From([]int{1, 2}), From([]int{3, 4}))),
+		"GroupBy":         testSeqOf(GroupBy(source, ids)),
+		"GroupAdjacent":   testSeqOf(GroupAdjacent(source, "Id")),
+		"Unpivot":         testSeqOf(Unpivot(source, "Id", "Name")),
+		"Groups":          testSeq2Of(source.GroupListsOf("Name").By("Id").Groups()),
+		"Aggregate":       testSeq2Of(source.GroupBy("Name").Aggregate(Count(), Sum("Id")).HavingThis(func(key any, value any) bool { return key != "B" }).Groups()),
+		"LookupAll":       testSeq2Of(ToLookup[int, string](source.GroupListsOf("Name").By("Id")).All()),
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func TestReiteration(t *testing.T) {
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("twice", func(t *testing.T) {
+
+		for name, query := range testReiterableQueries() {
+			first := query()
+			if first == "[]" {
+				t.Errorf("%s: Expected items but got none", name)
+			}
+
+			if second := query(); second != first {
+				t.Errorf("%s: Expected %v but got %v", name, first, second)
+			}
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+
+	t.Run("concurrently", func(t *testing.T) {
+
+		for name, query := range testReiterableQueries() {
+			expected := query()
+
+			var wg sync.WaitGroup
+			for range 8 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if result := query(); result != expected {
+						t.Errorf("%s: Expected %v but got %v", name, expected, result)
+					}
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	//----------------------------------------------------------------------------//
+}